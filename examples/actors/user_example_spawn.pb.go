@@ -0,0 +1,60 @@
+// Code generated by protoc-gen-spawn-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-spawn-go v0.1.0
+// source: user_example.proto
+
+package actors
+
+import (
+	context "context"
+	actors "github.com/eigr/spawn-go-sdk/spawn/actors"
+	system "github.com/eigr/spawn-go-sdk/spawn/system"
+	proto "google.golang.org/protobuf/proto"
+)
+
+const (
+	// UserActor_ActorName is the name the UserActor actor is registered with by default.
+	UserActor_ActorName                 = "UserActor"
+	UserActor_ChangeUserName_ActionName = "ChangeUserName"
+)
+
+// UserActorServer is the server API of the UserActor actor, whose state is of type S.
+type UserActorServer[S proto.Message] interface {
+	ChangeUserName(ctx *actors.TypedContext[S], req *ChangeUserNamePayload) (actors.TypedValue[S, *ChangeUserNameResponse], error)
+}
+
+// RegisterUserActor registers impl as the UserActor actor of the system, with one action per rpc.
+// The actor name defaults to UserActor_ActorName when config.Name is empty.
+func RegisterUserActor[S proto.Message](sys *system.System, impl UserActorServer[S], config actors.ActorConfig) *actors.TypedActor[S] {
+	if config.Name == "" {
+		config.Name = UserActor_ActorName
+	}
+
+	actor := actors.NewActor[S](config)
+	actors.AddAction(actor, UserActor_ChangeUserName_ActionName, impl.ChangeUserName)
+
+	sys.RegisterActor(actor.Actor)
+	return actor
+}
+
+// UserActorClient invokes the actions of the UserActor actor.
+type UserActorClient struct {
+	system    *system.System
+	actorName string
+}
+
+// NewUserActorClient creates a client of the UserActor actor of the system.
+func NewUserActorClient(sys *system.System) *UserActorClient {
+	return &UserActorClient{system: sys, actorName: UserActor_ActorName}
+}
+
+// WithActorName returns a client of the actor registered under name instead of UserActor_ActorName.
+func (c *UserActorClient) WithActorName(name string) *UserActorClient {
+	return &UserActorClient{system: c.system, actorName: name}
+}
+
+// ChangeUserName invokes the ChangeUserName action. An empty id targets the actor itself,
+// otherwise its Unnamed instance named id.
+func (c *UserActorClient) ChangeUserName(ctx context.Context, id string, req *ChangeUserNamePayload, options ...system.Options) (*ChangeUserNameResponse, error) {
+	return system.InvokeTyped[*ChangeUserNameResponse](ctx, c.system, c.actorName, id, UserActor_ChangeUserName_ActionName, req, options...)
+}
@@ -1,19 +1,16 @@
 package logic
 
 import (
-	"fmt"
 	"log"
 
 	domain "examples/actors"
 
 	spawn "github.com/eigr/spawn-go-sdk/spawn/actors"
-
-	"google.golang.org/protobuf/proto"
 )
 
-func BuilUserActor() *spawn.Actor {
-	// Defines the actor configuration
-	actorConfig := spawn.ActorConfig{
+// UserActorConfig defines the actor configuration
+func UserActorConfig() spawn.ActorConfig {
+	return spawn.ActorConfig{
 		Name:               "UserActor",         // Name of ator
 		StateType:          &domain.UserState{}, // State type
 		Kind:               spawn.Named,         // Actor Type (Named)
@@ -21,28 +18,19 @@ func BuilUserActor() *spawn.Actor {
 		SnapshotTimeout:    60,                  // Snapshot timeout
 		DeactivatedTimeout: 120,                 // Deactivation timeout
 	}
+}
+
+// UserActor implements the UserActor service declared in user_example.proto
+type UserActor struct{}
+
+// ChangeUserName receives the payload and state already converted
+func (a *UserActor) ChangeUserName(ctx *spawn.TypedContext[*domain.UserState], input *domain.ChangeUserNamePayload) (spawn.TypedValue[*domain.UserState, *domain.ChangeUserNameResponse], error) {
+	log.Printf("Received invoke on Action ChangeUserName. Payload: %v", input)
+
+	// Updates the status and prepares the response
+	newState := &domain.UserState{Name: input.GetNewName()}
+	response := &domain.ChangeUserNameResponse{ResponseStatus: domain.ChangeUserNameResponse_OK}
 
-	// Creates an actor directly
-	userActor := spawn.ActorOf(actorConfig)
-
-	// Define a simple action for the actor
-	userActor.AddAction("ChangeUserName", func(ctx *spawn.ActorContext, payload proto.Message) (spawn.Value, error) {
-		// Convert payload to expected type
-		log.Printf("Received invoke on Action ChangeUserName. Payload: %v", payload)
-		input, ok := payload.(*domain.ChangeUserNamePayload)
-		if !ok {
-			return spawn.Value{}, fmt.Errorf("invalid payload type")
-		}
-
-		// Updates the status and prepares the response
-		newState := &domain.UserState{Name: input.NewName}
-		response := &domain.ChangeUserNameResponse{ResponseStatus: domain.ChangeUserNameResponse_OK}
-
-		// Returns response to caller and persist new state
-		return spawn.Of(response).
-			State(newState).
-			Materialize(), nil
-	})
-
-	return userActor
+	// Returns response to caller and persist new state
+	return spawn.Reply(newState, response), nil
 }
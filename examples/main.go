@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"log"
+	"log/slog"
 	"time"
 
 	domain "examples/actors"
@@ -11,27 +13,35 @@ import (
 )
 
 func main() {
-	userActor := logic.BuilUserActor()
-
 	// Initializes the Spawn system
 	system := actorSystem.NewSystem("spawn-system").
 		UseProxyPort(9001).
 		ExposePort(8090).
-		RegisterActor(userActor)
+		WithLogger(slog.Default())
+
+	// Registers the actor generated from the UserActor service
+	domain.RegisterUserActor(system, &logic.UserActor{}, logic.UserActorConfig())
 
 	// Start the system
 	if err := system.Start(); err != nil {
 		log.Fatalf("Failed to start Actor System: %v", err)
 	}
 
+	// Reports the failures of the ActorHost while the system runs
+	go func() {
+		for err := range system.Errors() {
+			log.Printf("Actor System error: %v", err)
+		}
+	}()
+
 	time.Sleep(5 * time.Second)
 
-	resp, _ := system.Invoke(
-		"spawn-system",
-		"UserActor",
-		"ChangeUserName",
-		&domain.ChangeUserNamePayload{NewName: "John Doe"},
-		actorSystem.Options{})
+	// Invokes the actor through the generated client
+	client := domain.NewUserActorClient(system)
+	resp, _ := client.ChangeUserName(
+		context.Background(),
+		"",
+		&domain.ChangeUserNamePayload{NewName: "John Doe"})
 
 	log.Printf("Response: %v", resp)
 
@@ -2,6 +2,7 @@ package actors
 
 import (
 	"sync"
+	"time"
 
 	"google.golang.org/protobuf/proto"
 )
@@ -9,6 +10,31 @@ import (
 // ActionHandler defines the function of a Protobuf-supported action.
 type ActionHandler func(ctx *ActorContext, payload proto.Message) (Value, error)
 
+// TimerAction is an action triggered by the proxy at a fixed interval.
+// Timer ticks only carry the actor's state, so the handler payload is always nil.
+type TimerAction struct {
+	Every   time.Duration
+	Handler ActionHandler
+}
+
+// InfiniteRetention keeps the events of a sourceable actor forever.
+const InfiniteRetention time.Duration = -1
+
+// ProjectionSubject is an event stream consumed by a projection actor.
+type ProjectionSubject struct {
+	Actor     string    // Actor whose events are consumed
+	Action    string    // Action of the projection that handles the events
+	StartTime time.Time // Zero consumes the stream from the beginning
+}
+
+// ProjectionSettings configures event sourcing for an actor.
+type ProjectionSettings struct {
+	Subjects             []ProjectionSubject // Only valid for Projection actors
+	Sourceable           bool                // The actor emits events that projections can consume
+	EventsRetention      time.Duration       // Zero uses the proxy default, see InfiniteRetention
+	StrictEventsOrdering bool
+}
+
 // Actor represents an actor in Spawn.
 type Actor struct {
 	Name               string
@@ -19,7 +45,11 @@ type Actor struct {
 	DeactivatedTimeout int64
 	MinPoolSize        int32
 	MaxPoolSize        int32
+	Channels           []Channel
+	ProjectionSettings *ProjectionSettings
+	InitialState       func() proto.Message
 	Actions            map[string]ActionHandler
+	TimerActions       map[string]TimerAction
 	mu                 sync.Mutex
 }
 
@@ -33,6 +63,9 @@ type ActorConfig struct {
 	DeactivatedTimeout int64
 	MinPoolSize        int32
 	MaxPoolSize        int32
+	Channels           []Channel
+	ProjectionSettings *ProjectionSettings
+	InitialState       func() proto.Message // Optional, defaults to an empty StateType
 }
 
 // ActorOf creates a new actor instance (preferred method for API consistency).
@@ -47,6 +80,39 @@ func (a *Actor) AddAction(name string, handler ActionHandler) {
 	a.Actions[name] = handler
 }
 
+// AddTimerAction adds an action that the proxy triggers every interval.
+// The interval is rounded up to whole seconds.
+func (a *Actor) AddTimerAction(name string, every time.Duration, handler ActionHandler) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.TimerActions[name] = TimerAction{Every: every, Handler: handler}
+}
+
+// Handler returns the handler registered for the given action or timer action.
+func (a *Actor) Handler(name string) (ActionHandler, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if handler, ok := a.Actions[name]; ok {
+		return handler, true
+	}
+	if timer, ok := a.TimerActions[name]; ok {
+		return timer.Handler, true
+	}
+	return nil, false
+}
+
+// NewState returns the state of an actor that has no state yet.
+// It uses InitialState when set, otherwise a zero value of StateType, or nil when neither is set.
+func (a *Actor) NewState() proto.Message {
+	if a.InitialState != nil {
+		return a.InitialState()
+	}
+	if a.StateType != nil {
+		return a.StateType.ProtoReflect().New().Interface()
+	}
+	return nil
+}
+
 // NewActor creates a new actor instance (legacy method, can be deprecated if needed).
 func newActor(config ActorConfig) *Actor {
 	return &Actor{
@@ -56,6 +122,12 @@ func newActor(config ActorConfig) *Actor {
 		Stateful:           config.Stateful,
 		SnapshotTimeout:    config.SnapshotTimeout,
 		DeactivatedTimeout: config.DeactivatedTimeout,
+		MinPoolSize:        config.MinPoolSize,
+		MaxPoolSize:        config.MaxPoolSize,
+		Channels:           config.Channels,
+		ProjectionSettings: config.ProjectionSettings,
+		InitialState:       config.InitialState,
 		Actions:            make(map[string]ActionHandler),
+		TimerActions:       make(map[string]TimerAction),
 	}
 }
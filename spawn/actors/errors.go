@@ -0,0 +1,44 @@
+package actors
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Error codes reported by the ActorHost. Handlers are free to use their own codes.
+const (
+	CodeActorNotFound  = "ACTOR_NOT_FOUND"
+	CodeActionNotFound = "ACTION_NOT_FOUND"
+	CodeInvalidPayload = "INVALID_PAYLOAD" // The payload could not be decoded
+	CodeInvalidState   = "INVALID_STATE"   // The state does not match the StateType of the actor
+	CodeHandlerFailed  = "HANDLER_FAILED"
+)
+
+// Error is a failure of an action handler that reaches the caller with its code, message and detail.
+// Any other error returned by a handler reaches the caller with CodeHandlerFailed and its text as message.
+type Error struct {
+	Code    string
+	Message string
+	Detail  proto.Message // Optional, must be a registered message type to be decoded by the caller
+}
+
+// NewError creates an error with the given code and message.
+func NewError(code string, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// Errorf creates an error with the given code and a formatted message.
+func Errorf(code string, format string, args ...interface{}) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// WithDetail attaches a Protobuf message describing the error.
+func (e *Error) WithDetail(detail proto.Message) *Error {
+	e.Detail = detail
+	return e
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
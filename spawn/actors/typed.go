@@ -0,0 +1,168 @@
+package actors
+
+import (
+	"fmt"
+	"reflect"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// TypedActor is an actor whose state is of type S.
+// It embeds the underlying Actor, which is the one registered in the system.
+type TypedActor[S proto.Message] struct {
+	*Actor
+}
+
+// NewActor creates an actor whose state is of type S.
+// StateType defaults to an empty S when it is not set in the config. It must be set
+// when S is an interface such as proto.Message, NewActor panics otherwise.
+func NewActor[S proto.Message](config ActorConfig) *TypedActor[S] {
+	if config.StateType == nil {
+		var zero S
+		if any(zero) == nil {
+			panic(fmt.Sprintf("actors: NewActor of actor %s needs config.StateType since the state type %s is an interface, use a concrete message pointer such as *MyState",
+				config.Name, reflect.TypeOf((*S)(nil)).Elem()))
+		}
+		config.StateType = zero.ProtoReflect().Type().New().Interface()
+	}
+	return &TypedActor[S]{Actor: newActor(config)}
+}
+
+// TypedContext provides context for the handler of a typed action.
+type TypedContext[S proto.Message] struct {
+	*ActorContext
+	state S
+}
+
+// State returns the current state of the actor.
+func (c *TypedContext[S]) State() S {
+	return c.state
+}
+
+// TypedValue is the result of a typed action.
+// The zero TypedValue keeps the current state and replies with no payload.
+type TypedValue[S proto.Message, Resp proto.Message] struct {
+	builder *ValueBuilder
+}
+
+// Reply creates the result of a typed action. A typed nil state keeps the current state,
+// a typed nil response replies with no payload. Use KeepState and NoResponse to leave
+// either out, since an untyped nil does not tell Reply their types.
+func Reply[S proto.Message, Resp proto.Message](state S, response Resp) TypedValue[S, Resp] {
+	builder := Of(nil)
+	if !isNil(response) {
+		builder.value.Response = response
+	}
+	if !isNil(state) {
+		builder.State(state)
+	}
+	return TypedValue[S, Resp]{builder: builder}
+}
+
+// KeepState creates the result of a typed action that keeps the current state, e.g.
+//
+//	return actors.KeepState[*UserState](response), nil
+func KeepState[S proto.Message, Resp proto.Message](response Resp) TypedValue[S, Resp] {
+	var state S
+	return Reply(state, response)
+}
+
+// NoResponse creates the result of a typed action that replies with no payload, e.g. when forwarding:
+//
+//	return actors.NoResponse[*UserState, *ChangeUserNameResponse](state).Forward("AuditActor", "Record"), nil
+func NoResponse[S proto.Message, Resp proto.Message](state S) TypedValue[S, Resp] {
+	var response Resp
+	return Reply(state, response)
+}
+
+// Broadcast publishes msg to all actors subscribed to channelGroup.
+func (v TypedValue[S, Resp]) Broadcast(channelGroup string, msg proto.Message) TypedValue[S, Resp] {
+	v = v.withBuilder()
+	v.builder.Broadcast(channelGroup, msg)
+	return v
+}
+
+// Effects adds side effects to be released after the action completes.
+func (v TypedValue[S, Resp]) Effects(effects ...SideEffect) TypedValue[S, Resp] {
+	v = v.withBuilder()
+	v.builder.Effects(effects...)
+	return v
+}
+
+// Pipe hands the response of this action over to another actor's action.
+func (v TypedValue[S, Resp]) Pipe(actor string, action string) TypedValue[S, Resp] {
+	v = v.withBuilder()
+	v.builder.Pipe(actor, action)
+	return v
+}
+
+// Forward hands the original input of this action over to another actor's action.
+func (v TypedValue[S, Resp]) Forward(actor string, action string) TypedValue[S, Resp] {
+	v = v.withBuilder()
+	v.builder.Forward(actor, action)
+	return v
+}
+
+// Tags replaces the tags of the actor instance.
+func (v TypedValue[S, Resp]) Tags(tags map[string]string) TypedValue[S, Resp] {
+	v = v.withBuilder()
+	v.builder.Tags(tags)
+	return v
+}
+
+// withBuilder returns v with a builder, so that the zero TypedValue can be built upon.
+func (v TypedValue[S, Resp]) withBuilder() TypedValue[S, Resp] {
+	if v.builder == nil {
+		v.builder = Of(nil)
+	}
+	return v
+}
+
+// Materialize returns the untyped Value.
+func (v TypedValue[S, Resp]) Materialize() Value {
+	if v.builder == nil {
+		return Value{}
+	}
+	return v.builder.Materialize()
+}
+
+// TypedActionHandler defines the function of an action with typed state, request and response.
+type TypedActionHandler[S proto.Message, Req proto.Message, Resp proto.Message] func(ctx *TypedContext[S], req Req) (TypedValue[S, Resp], error)
+
+// AddAction adds a typed action to the actor. The state and the payload are converted
+// before calling the handler, and an error describing the mismatch is returned at
+// invocation time when they are not of the expected types.
+func AddAction[S proto.Message, Req proto.Message, Resp proto.Message](actor *TypedActor[S], name string, handler TypedActionHandler[S, Req, Resp]) {
+	actor.AddAction(name, func(ctx *ActorContext, payload proto.Message) (Value, error) {
+		var state S
+		if ctx.CurrentState != nil {
+			typedState, ok := ctx.CurrentState.(S)
+			if !ok {
+				return Value{}, fmt.Errorf("action %s of actor %s expects state %T, got %T", name, actor.Name, state, ctx.CurrentState)
+			}
+			state = typedState
+		}
+
+		// Noop payloads are handed over as a nil request
+		var req Req
+		if payload != nil {
+			typedReq, ok := payload.(Req)
+			if !ok {
+				return Value{}, fmt.Errorf("action %s of actor %s expects payload %T, got %T", name, actor.Name, req, payload)
+			}
+			req = typedReq
+		}
+
+		value, err := handler(&TypedContext[S]{ActorContext: ctx, state: state}, req)
+		if err != nil {
+			return Value{}, err
+		}
+
+		return value.Materialize(), nil
+	})
+}
+
+// isNil reports whether m is nil or a typed nil pointer.
+func isNil(m proto.Message) bool {
+	return m == nil || !m.ProtoReflect().IsValid()
+}
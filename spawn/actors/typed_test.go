@@ -0,0 +1,125 @@
+package actors
+
+import (
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestAddAction(t *testing.T) {
+	tests := []struct {
+		name         string
+		state        proto.Message
+		payload      proto.Message
+		value        func(ctx *TypedContext[*wrapperspb.Int64Value], req *wrapperspb.StringValue) TypedValue[*wrapperspb.Int64Value, *wrapperspb.StringValue]
+		wantState    proto.Message
+		wantResponse proto.Message
+		wantErr      string
+	}{
+		{
+			name:    "typed state and payload",
+			state:   wrapperspb.Int64(1),
+			payload: wrapperspb.String("hello"),
+			value: func(ctx *TypedContext[*wrapperspb.Int64Value], req *wrapperspb.StringValue) TypedValue[*wrapperspb.Int64Value, *wrapperspb.StringValue] {
+				return Reply(wrapperspb.Int64(ctx.State().GetValue()+1), wrapperspb.String(req.GetValue()+" world"))
+			},
+			wantState:    wrapperspb.Int64(2),
+			wantResponse: wrapperspb.String("hello world"),
+		},
+		{
+			name:    "mismatched state",
+			state:   wrapperspb.String("not an int"),
+			payload: wrapperspb.String("hello"),
+			wantErr: "expects state *wrapperspb.Int64Value, got *wrapperspb.StringValue",
+		},
+		{
+			name:    "mismatched payload",
+			state:   wrapperspb.Int64(1),
+			payload: wrapperspb.Int64(1),
+			wantErr: "expects payload *wrapperspb.StringValue, got *wrapperspb.Int64Value",
+		},
+		{
+			name:  "noop payload",
+			state: wrapperspb.Int64(1),
+			value: func(ctx *TypedContext[*wrapperspb.Int64Value], req *wrapperspb.StringValue) TypedValue[*wrapperspb.Int64Value, *wrapperspb.StringValue] {
+				if req != nil {
+					t.Errorf("req = %v, want nil", req)
+				}
+				return KeepState[*wrapperspb.Int64Value](wrapperspb.String("noop"))
+			},
+			wantResponse: wrapperspb.String("noop"),
+		},
+		{
+			name:    "nil state keeps the state",
+			payload: wrapperspb.String("hello"),
+			value: func(ctx *TypedContext[*wrapperspb.Int64Value], req *wrapperspb.StringValue) TypedValue[*wrapperspb.Int64Value, *wrapperspb.StringValue] {
+				if ctx.State() != nil {
+					t.Errorf("State() = %v, want nil", ctx.State())
+				}
+				return KeepState[*wrapperspb.Int64Value](req)
+			},
+			wantResponse: wrapperspb.String("hello"),
+		},
+		{
+			name:    "no response",
+			state:   wrapperspb.Int64(1),
+			payload: wrapperspb.String("hello"),
+			value: func(ctx *TypedContext[*wrapperspb.Int64Value], req *wrapperspb.StringValue) TypedValue[*wrapperspb.Int64Value, *wrapperspb.StringValue] {
+				return NoResponse[*wrapperspb.Int64Value, *wrapperspb.StringValue](wrapperspb.Int64(3))
+			},
+			wantState: wrapperspb.Int64(3),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actor := NewActor[*wrapperspb.Int64Value](ActorConfig{Name: "Counter", Kind: Named})
+			AddAction(actor, "act", func(ctx *TypedContext[*wrapperspb.Int64Value], req *wrapperspb.StringValue) (TypedValue[*wrapperspb.Int64Value, *wrapperspb.StringValue], error) {
+				if tt.value == nil {
+					t.Fatal("handler called")
+				}
+				return tt.value(ctx, req), nil
+			})
+			handler, ok := actor.Handler("act")
+			if !ok {
+				t.Fatal("action not registered")
+			}
+
+			value, err := handler(NewActorContext(tt.state), tt.payload)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !proto.Equal(value.State, tt.wantState) {
+				t.Errorf("State = %v, want %v", value.State, tt.wantState)
+			}
+			if !proto.Equal(value.Response, tt.wantResponse) {
+				t.Errorf("Response = %v, want %v", value.Response, tt.wantResponse)
+			}
+		})
+	}
+}
+
+func TestTypedValueZero(t *testing.T) {
+	value := TypedValue[*wrapperspb.Int64Value, *wrapperspb.StringValue]{}.
+		Forward("AuditActor", "Record").
+		Tags(map[string]string{"audited": "true"}).
+		Materialize()
+
+	if value.State != nil || value.Response != nil {
+		t.Errorf("State, Response = %v, %v, want nil", value.State, value.Response)
+	}
+	if value.Workflow == nil || value.Workflow.Forward == nil || value.Workflow.Forward.Actor != "AuditActor" {
+		t.Errorf("Workflow = %+v, want a forward to AuditActor", value.Workflow)
+	}
+	if value.Tags["audited"] != "true" {
+		t.Errorf("Tags = %v, want audited", value.Tags)
+	}
+}
@@ -1,6 +1,11 @@
 package actors
 
 import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
 	"google.golang.org/protobuf/proto"
 )
 
@@ -10,18 +15,80 @@ type Kind string
 const (
 	Unnamed    Kind = "UNNAMED"
 	Named      Kind = "NAMED"
-	Pooled     Kind = "Pooled"
-	Task       Kind = "Task"
-	Projection Kind = "Projection"
+	Pooled     Kind = "POOLED"
+	Proxy      Kind = "PROXY"
+	Task       Kind = "TASK"
+	Projection Kind = "PROJECTION"
 )
 
-type Workflow struct{}
+// ParseKind parses a kind name case-insensitively, e.g. "pooled" or "Named".
+func ParseKind(name string) (Kind, error) {
+	kind := Kind(strings.ToUpper(strings.TrimSpace(name)))
+	switch kind {
+	case Unnamed, Named, Pooled, Proxy, Task, Projection:
+		return kind, nil
+	default:
+		return "", fmt.Errorf("unknown actor kind %q", name)
+	}
+}
+
+// DefaultChannelAction is the action that receives channel messages when a subscription does not name one.
+const DefaultChannelAction = "receive"
+
+// Channel subscribes an actor to a Pub-Sub topic.
+// Messages are routed to Action, or to DefaultChannelAction when it is empty.
+type Channel struct {
+	Topic  string
+	Action string
+}
+
+// Workflow describes what the proxy must do once an action has completed.
+// Pipe and Forward are mutually exclusive.
+type Workflow struct {
+	Broadcast *Broadcast
+	Effects   []SideEffect
+	Pipe      *Pipe
+	Forward   *Forward
+}
+
+// Broadcast publishes a message to every actor subscribed to a channel group.
+// A nil Payload is sent as a Noop.
+type Broadcast struct {
+	ChannelGroup string
+	Payload      proto.Message
+}
+
+// SideEffect is a fire-and-forget invocation of another actor. The proxy only
+// releases side effects after the state returned by the action has been committed.
+type SideEffect struct {
+	System      string // Defaults to the system of the invoking actor.
+	Actor       string
+	Action      string
+	Payload     proto.Message
+	Metadata    map[string]string
+	ScheduledTo time.Time // Zero means as soon as possible.
+}
+
+// Pipe sends the response of the current action to the input of another actor's action.
+// The caller receives the response of that action instead.
+type Pipe struct {
+	Actor  string
+	Action string
+}
+
+// Forward sends the input of the current action to another actor's action.
+// The caller receives the response of that action instead.
+type Forward struct {
+	Actor  string
+	Action string
+}
 
 // Value represents the return on a stock.
 type Value struct {
 	State    proto.Message
 	Response proto.Message
-	Workflow interface{}
+	Workflow *Workflow
+	Tags     map[string]string
 }
 
 // ValueBuilder is the builder to create an instance of Value.
@@ -42,18 +109,208 @@ func (b *ValueBuilder) State(state proto.Message) *ValueBuilder {
 	return b
 }
 
+// Tags replaces the tags of the actor instance. Tags are kept unchanged when not set.
+func (b *ValueBuilder) Tags(tags map[string]string) *ValueBuilder {
+	b.value.Tags = tags
+	return b
+}
+
 // Workflow creates a new flow.
-func (b *ValueBuilder) Workflow(workflow interface{}) *ValueBuilder {
+func (b *ValueBuilder) Workflow(workflow *Workflow) *ValueBuilder {
 	b.value.Workflow = workflow
 	return b
 }
 
+// Broadcast publishes msg to all actors subscribed to channelGroup.
+func (b *ValueBuilder) Broadcast(channelGroup string, msg proto.Message) *ValueBuilder {
+	b.workflow().Broadcast = &Broadcast{ChannelGroup: channelGroup, Payload: msg}
+	return b
+}
+
+// Effects adds side effects to be released after the action completes.
+func (b *ValueBuilder) Effects(effects ...SideEffect) *ValueBuilder {
+	workflow := b.workflow()
+	workflow.Effects = append(workflow.Effects, effects...)
+	return b
+}
+
+// Pipe hands the response of this action over to another actor's action.
+func (b *ValueBuilder) Pipe(actor string, action string) *ValueBuilder {
+	b.workflow().Pipe = &Pipe{Actor: actor, Action: action}
+	return b
+}
+
+// Forward hands the original input of this action over to another actor's action.
+func (b *ValueBuilder) Forward(actor string, action string) *ValueBuilder {
+	b.workflow().Forward = &Forward{Actor: actor, Action: action}
+	return b
+}
+
+func (b *ValueBuilder) workflow() *Workflow {
+	if b.value.Workflow == nil {
+		b.value.Workflow = &Workflow{}
+	}
+	return b.value.Workflow
+}
+
 // Materialize finalizes the builder and returns the constructed Value.
 func (b *ValueBuilder) Materialize() Value {
 	return b.value
 }
 
+// ActorId identifies an actor instance.
+type ActorId struct {
+	Name   string
+	System string
+	Parent string // Name of the registered actor an Unnamed instance was spawned from
+}
+
+// Invoker performs an invocation on behalf of the caller actor.
+type Invoker func(ctx context.Context, caller ActorId, system string, actor string, action string, request proto.Message, options map[string]interface{}) (proto.Message, error)
+
 // ActorContext provides context for an actor's handler.
 type ActorContext struct {
 	CurrentState proto.Message
+	ctx          context.Context
+	self         ActorId
+	caller       *ActorId
+	tags         map[string]string
+	metadata     map[string]string
+	propagated   map[string]string
+	invoker      Invoker
+}
+
+// ActorContextOption configures an ActorContext created with NewActorContext.
+type ActorContextOption func(*ActorContext)
+
+// NewActorContext creates the context of an invocation. The ActorHost creates one for every invocation
+// it receives, handlers can be unit-tested with contexts created by hand.
+func NewActorContext(state proto.Message, options ...ActorContextOption) *ActorContext {
+	c := &ActorContext{CurrentState: state}
+	for _, option := range options {
+		option(c)
+	}
+	return c
+}
+
+// WithContext sets the request-scoped context of the invocation.
+func WithContext(ctx context.Context) ActorContextOption {
+	return func(c *ActorContext) {
+		c.ctx = ctx
+	}
+}
+
+// WithSelf sets the identity of the actor instance handling the invocation.
+func WithSelf(self ActorId) ActorContextOption {
+	return func(c *ActorContext) {
+		c.self = self
+	}
+}
+
+// WithCaller sets the identity of the invoking actor.
+func WithCaller(caller ActorId) ActorContextOption {
+	return func(c *ActorContext) {
+		c.caller = &caller
+	}
+}
+
+// WithTags sets the tags of the actor instance.
+func WithTags(tags map[string]string) ActorContextOption {
+	return func(c *ActorContext) {
+		c.tags = tags
+	}
+}
+
+// WithMetadata sets the metadata sent along with the invocation.
+func WithMetadata(metadata map[string]string) ActorContextOption {
+	return func(c *ActorContext) {
+		c.metadata = metadata
+	}
+}
+
+// WithPropagatedMetadata sets the metadata merged into the invocations made with Invoke.
+func WithPropagatedMetadata(metadata map[string]string) ActorContextOption {
+	return func(c *ActorContext) {
+		c.propagated = metadata
+	}
+}
+
+// WithInvoker sets the invoker used by Invoke.
+func WithInvoker(invoker Invoker) ActorContextOption {
+	return func(c *ActorContext) {
+		c.invoker = invoker
+	}
+}
+
+// Context returns the request-scoped context of the invocation. It is cancelled when the
+// proxy disconnects and carries the deadline of the caller, if any.
+func (c *ActorContext) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// Invoke calls an action of another actor through the proxy connection of the system,
+// with the current actor as the caller. It accepts the same options as System.Invoke,
+// the propagated metadata of the current invocation is merged into options["metadata"].
+// The call is bound to Context, so it is aborted when the current invocation is.
+// An empty system defaults to the system of the current actor.
+func (c *ActorContext) Invoke(system string, actor string, action string, request proto.Message, options map[string]interface{}) (proto.Message, error) {
+	if c.invoker == nil {
+		return nil, fmt.Errorf("actor context of %s cannot invoke other actors", c.self.Name)
+	}
+
+	if system == "" {
+		system = c.self.System
+	}
+
+	invokeOptions := make(map[string]interface{}, len(options)+1)
+	for key, value := range options {
+		invokeOptions[key] = value
+	}
+
+	// Invalid metadata is left untouched so that the invocation reports it
+	explicit, ok := options["metadata"].(map[string]string)
+	if _, hasMetadata := options["metadata"]; len(c.propagated) > 0 && (ok || !hasMetadata) {
+		metadata := copyMap(c.propagated)
+		for key, value := range explicit {
+			metadata[key] = value
+		}
+		invokeOptions["metadata"] = metadata
+	}
+
+	return c.invoker(c.Context(), c.self, system, actor, action, request, invokeOptions)
+}
+
+// Self returns the identity of the actor instance handling the invocation.
+func (c *ActorContext) Self() ActorId {
+	return c.self
+}
+
+// Caller returns the identity of the invoking actor.
+// The boolean is false when the invocation did not come from an actor, e.g. from System.Invoke.
+func (c *ActorContext) Caller() (ActorId, bool) {
+	if c.caller == nil {
+		return ActorId{}, false
+	}
+	return *c.caller, true
+}
+
+// Metadata returns a copy of the metadata sent along with the current invocation.
+func (c *ActorContext) Metadata() map[string]string {
+	return copyMap(c.metadata)
+}
+
+// Tags returns a copy of the tags of the actor instance.
+func (c *ActorContext) Tags() map[string]string {
+	return copyMap(c.tags)
+}
+
+func copyMap(m map[string]string) map[string]string {
+	c := make(map[string]string, len(m))
+	for key, value := range m {
+		c[key] = value
+	}
+	return c
 }
@@ -0,0 +1,32 @@
+package actors
+
+import "testing"
+
+func TestParseKind(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Kind
+		wantErr bool
+	}{
+		{name: "NAMED", want: Named},
+		{name: "unnamed", want: Unnamed},
+		{name: "Pooled", want: Pooled},
+		{name: " proxy ", want: Proxy},
+		{name: "task", want: Task},
+		{name: "projection", want: Projection},
+		{name: "", wantErr: true},
+		{name: "singleton", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseKind(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseKind(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseKind(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+
+	"google.golang.org/protobuf/compiler/protogen"
+)
+
+const (
+	actorsPackage = protogen.GoImportPath("github.com/eigr/spawn-go-sdk/spawn/actors")
+	systemPackage = protogen.GoImportPath("github.com/eigr/spawn-go-sdk/spawn/system")
+	protoPackage  = protogen.GoImportPath("google.golang.org/protobuf/proto")
+)
+
+// generateFile generates the actors and clients of every service in file into a _spawn.pb.go file.
+func generateFile(gen *protogen.Plugin, file *protogen.File) error {
+	for _, service := range file.Services {
+		for _, method := range service.Methods {
+			if method.Desc.IsStreamingClient() || method.Desc.IsStreamingServer() {
+				return fmt.Errorf("rpc %s.%s: streaming rpcs cannot be mapped to actor actions", service.GoName, method.GoName)
+			}
+			if method.GoName == withActorNameMethod {
+				return fmt.Errorf("rpc %s.%s: the name is taken by the %s method of the generated client", service.GoName, method.GoName, withActorNameMethod)
+			}
+			if err := checkActorIdFields(method.Input); err != nil {
+				return fmt.Errorf("rpc %s.%s: %w", service.GoName, method.GoName, err)
+			}
+		}
+	}
+
+	filename := file.GeneratedFilenamePrefix + "_spawn.pb.go"
+	g := gen.NewGeneratedFile(filename, file.GoImportPath)
+
+	g.P("// Code generated by protoc-gen-spawn-go. DO NOT EDIT.")
+	g.P("// versions:")
+	g.P("// \tprotoc-gen-spawn-go ", version)
+	g.P("// source: ", file.Desc.Path())
+	g.P()
+	g.P("package ", file.GoPackageName)
+	g.P()
+
+	for _, service := range file.Services {
+		generateNames(g, service)
+		generateServer(g, service)
+		generateClient(g, service)
+	}
+
+	return nil
+}
+
+// generateNames generates the constants holding the actor and action names of the service.
+func generateNames(g *protogen.GeneratedFile, service *protogen.Service) {
+	g.P("const (")
+	g.P("// ", service.GoName, "_ActorName is the name the ", service.GoName, " actor is registered with by default.")
+	g.P(service.GoName, `_ActorName = "`, service.Desc.Name(), `"`)
+	for _, method := range service.Methods {
+		g.P(actionConstant(service, method), ` = "`, method.Desc.Name(), `"`)
+	}
+	g.P(")")
+	g.P()
+}
+
+// generateServer generates the typed server interface of the service and its Register function.
+func generateServer(g *protogen.GeneratedFile, service *protogen.Service) {
+	serverName := service.GoName + "Server"
+	protoMessage := g.QualifiedGoIdent(protoPackage.Ident("Message"))
+	typedContext := g.QualifiedGoIdent(actorsPackage.Ident("TypedContext"))
+	typedValue := g.QualifiedGoIdent(actorsPackage.Ident("TypedValue"))
+
+	g.P("// ", serverName, " is the server API of the ", service.GoName, " actor, whose state is of type S.")
+	g.AnnotateSymbol(serverName, protogen.Annotation{Location: service.Location})
+	g.P("type ", serverName, "[S ", protoMessage, "] interface {")
+	for _, method := range service.Methods {
+		g.AnnotateSymbol(serverName+"."+method.GoName, protogen.Annotation{Location: method.Location})
+		g.P(method.Comments.Leading, method.GoName, "(ctx *", typedContext, "[S], req *", g.QualifiedGoIdent(method.Input.GoIdent), ") (",
+			typedValue, "[S, *", g.QualifiedGoIdent(method.Output.GoIdent), "], error)")
+	}
+	g.P("}")
+	g.P()
+
+	g.P("// Register", service.GoName, " registers impl as the ", service.GoName, " actor of the system, with one action per rpc.")
+	g.P("// The actor name defaults to ", service.GoName, "_ActorName when config.Name is empty.")
+	g.P("func Register", service.GoName, "[S ", protoMessage, "](sys *", g.QualifiedGoIdent(systemPackage.Ident("System")),
+		", impl ", serverName, "[S], config ", g.QualifiedGoIdent(actorsPackage.Ident("ActorConfig")), ") *",
+		g.QualifiedGoIdent(actorsPackage.Ident("TypedActor")), "[S] {")
+	g.P("if config.Name == \"\" {")
+	g.P("config.Name = ", service.GoName, "_ActorName")
+	g.P("}")
+	g.P()
+	g.P("actor := ", g.QualifiedGoIdent(actorsPackage.Ident("NewActor")), "[S](config)")
+	for _, method := range service.Methods {
+		g.P(g.QualifiedGoIdent(actorsPackage.Ident("AddAction")), "(actor, ", actionConstant(service, method), ", impl.", method.GoName, ")")
+	}
+	g.P()
+	g.P("sys.RegisterActor(actor.Actor)")
+	g.P("return actor")
+	g.P("}")
+	g.P()
+}
+
+func actionConstant(service *protogen.Service, method *protogen.Method) string {
+	return service.GoName + "_" + method.GoName + "_ActionName"
+}
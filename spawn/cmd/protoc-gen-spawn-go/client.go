@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+
+	protocol "github.com/eigr/spawn-go-sdk/spawn/eigr/functions/protocol/actors"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const contextPackage = protogen.GoImportPath("context")
+
+// withActorNameMethod is the method of the generated clients that rpcs cannot be named after.
+const withActorNameMethod = "WithActorName"
+
+// generateClient generates the typed client of the service.
+func generateClient(g *protogen.GeneratedFile, service *protogen.Service) {
+	clientName := service.GoName + "Client"
+	system := g.QualifiedGoIdent(systemPackage.Ident("System"))
+
+	g.P("// ", clientName, " invokes the actions of the ", service.GoName, " actor.")
+	g.AnnotateSymbol(clientName, protogen.Annotation{Location: service.Location})
+	g.P("type ", clientName, " struct {")
+	g.P("system *", system)
+	g.P("actorName string")
+	g.P("}")
+	g.P()
+
+	g.P("// New", clientName, " creates a client of the ", service.GoName, " actor of the system.")
+	g.P("func New", clientName, "(sys *", system, ") *", clientName, " {")
+	g.P("return &", clientName, "{system: sys, actorName: ", service.GoName, "_ActorName}")
+	g.P("}")
+	g.P()
+
+	g.P("// ", withActorNameMethod, " returns a client of the actor registered under name instead of ", service.GoName, "_ActorName.")
+	g.P("func (c *", clientName, ") ", withActorNameMethod, "(name string) *", clientName, " {")
+	g.P("return &", clientName, "{system: c.system, actorName: name}")
+	g.P("}")
+	g.P()
+
+	for _, method := range service.Methods {
+		output := g.QualifiedGoIdent(method.Output.GoIdent)
+		options := g.QualifiedGoIdent(systemPackage.Ident("Options"))
+		routed := hasActorIdField(method.Input)
+		if routed {
+			g.P("// ", method.GoName, " invokes the ", method.Desc.Name(), " action. An empty id routes the request to the Unnamed")
+			g.P("// instance named by its (actor_id) field, otherwise to the instance named id.")
+		} else {
+			g.P("// ", method.GoName, " invokes the ", method.Desc.Name(), " action. An empty id targets the actor itself,")
+			g.P("// otherwise its Unnamed instance named id.")
+		}
+		g.AnnotateSymbol(clientName+"."+method.GoName, protogen.Annotation{Location: method.Location})
+		g.P("func (c *", clientName, ") ", method.GoName, "(ctx ", g.QualifiedGoIdent(contextPackage.Ident("Context")),
+			", id string, req *", g.QualifiedGoIdent(method.Input.GoIdent), ", options ...", options,
+			") (*", output, ", error) {")
+		if routed {
+			g.P("if id == \"\" {")
+			g.P("options = append([]", options, "{{\"route_by_actor_id\": true}}, options...)")
+			g.P("}")
+		}
+		g.P("return ", g.QualifiedGoIdent(systemPackage.Ident("InvokeTyped")), "[*", output, "](ctx, c.system, c.actorName, id, ",
+			actionConstant(service, method), ", req, options...)")
+		g.P("}")
+		g.P()
+	}
+}
+
+// hasActorIdField reports whether the message has a field annotated with (actor_id) = true,
+// whose value names the Unnamed instance the requests are routed to.
+func hasActorIdField(message *protogen.Message) bool {
+	for _, field := range message.Fields {
+		if isActorIdField(field) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkActorIdFields rejects the annotated fields that cannot name an instance, only
+// singular string and integer fields can.
+func checkActorIdFields(message *protogen.Message) error {
+	for _, field := range message.Fields {
+		if !isActorIdField(field) {
+			continue
+		}
+		if field.Desc.IsList() || field.Desc.IsMap() {
+			return fmt.Errorf("field %s annotated with (actor_id) must not be repeated", field.Desc.FullName())
+		}
+		switch field.Desc.Kind() {
+		case protoreflect.StringKind,
+			protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+			protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+			protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+			protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		default:
+			return fmt.Errorf("field %s annotated with (actor_id) must be a string or an integer, not %s", field.Desc.FullName(), field.Desc.Kind())
+		}
+	}
+	return nil
+}
+
+func isActorIdField(field *protogen.Field) bool {
+	annotated, _ := proto.GetExtension(field.Desc.Options(), protocol.E_ActorId).(bool)
+	return annotated
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	protocol "github.com/eigr/spawn-go-sdk/spawn/eigr/functions/protocol/actors"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestGenerateClientRoutesByActorId(t *testing.T) {
+	annotated := &descriptorpb.FieldOptions{}
+	proto.SetExtension(annotated, protocol.E_ActorId, true)
+
+	file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:       proto.String("routing.proto"),
+		Package:    proto.String("test"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{protocol.File_eigr_functions_protocol_actors_extensions_proto.Path()},
+		Options:    &descriptorpb.FileOptions{GoPackage: proto.String("example.com/test")},
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("Routed"),
+				Field: []*descriptorpb.FieldDescriptorProto{{
+					Name:     proto.String("user_id"),
+					JsonName: proto.String("userId"),
+					Number:   proto.Int32(1),
+					Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+					Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+					Options:  annotated,
+				}},
+			},
+			{Name: proto.String("Plain")},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("UserActor"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				{Name: proto.String("Routed"), InputType: proto.String(".test.Routed"), OutputType: proto.String(".test.Plain")},
+				{Name: proto.String("Plain"), InputType: proto.String(".test.Plain"), OutputType: proto.String(".test.Plain")},
+			},
+		}},
+	}, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code := generate(t, file)
+
+	routed := method(t, code, "Routed")
+	if !strings.Contains(routed, `{"route_by_actor_id": true}`) {
+		t.Errorf("Routed does not route by actor id:\n%s", routed)
+	}
+	plain := method(t, code, "Plain")
+	if strings.Contains(plain, "route_by_actor_id") {
+		t.Errorf("Plain routes by actor id:\n%s", plain)
+	}
+}
+
+// method returns the generated code of the client method named name.
+func method(t *testing.T, code string, name string) string {
+	t.Helper()
+
+	start := strings.Index(code, "func (c *UserActorClient) "+name+"(")
+	if start < 0 {
+		t.Fatalf("client method %s not generated:\n%s", name, code)
+	}
+	end := strings.Index(code[start:], "\n}\n")
+	return code[start : start+end]
+}
+
+func TestGenerateClientRejectsWithActorNameRpc(t *testing.T) {
+	file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:        proto.String("clash.proto"),
+		Package:     proto.String("test"),
+		Syntax:      proto.String("proto3"),
+		Options:     &descriptorpb.FileOptions{GoPackage: proto.String("example.com/test")},
+		MessageType: []*descriptorpb.DescriptorProto{{Name: proto.String("Plain")}},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: proto.String("UserActor"),
+			Method: []*descriptorpb.MethodDescriptorProto{
+				{Name: proto.String("WithActorName"), InputType: proto.String(".test.Plain"), OutputType: proto.String(".test.Plain")},
+			},
+		}},
+	}, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := runPlugin(t, file); err == nil || !strings.Contains(err.Error(), "WithActorName") {
+		t.Errorf("runPlugin() error = %v, want the WithActorName rpc to be rejected", err)
+	}
+}
@@ -0,0 +1,35 @@
+// protoc-gen-spawn-go is a protoc plugin that generates Spawn actors from Protobuf services.
+//
+// For every service it generates a typed server interface, a Register function that
+// wires each rpc to an actor action with the rpc input and output types, and a typed
+// client invoking those actions:
+//
+//	protoc --go_out=. --spawn-go_out=. user_example.proto
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+const version = "v0.1.0"
+
+func main() {
+	var flags flag.FlagSet
+
+	protogen.Options{ParamFunc: flags.Set}.Run(func(gen *protogen.Plugin) error {
+		gen.SupportedFeatures = uint64(pluginpb.CodeGeneratorResponse_FEATURE_PROTO3_OPTIONAL)
+		for _, file := range gen.Files {
+			if !file.Generate || len(file.Services) == 0 {
+				continue
+			}
+			if err := generateFile(gen, file); err != nil {
+				return fmt.Errorf("%s: %w", file.Desc.Path(), err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"testing"
+
+	"examples/actors"
+
+	"google.golang.org/protobuf/compiler/protogen"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/pluginpb"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+// generate runs the plugin on file and returns the content of the generated file.
+func generate(t *testing.T, file protoreflect.FileDescriptor) string {
+	t.Helper()
+
+	content, err := runPlugin(t, file)
+	if err != nil {
+		t.Fatalf("generateFile() error = %v", err)
+	}
+	return content
+}
+
+// runPlugin runs the plugin on file, returning the content of the generated file or the error of generateFile.
+func runPlugin(t *testing.T, file protoreflect.FileDescriptor) (string, error) {
+	t.Helper()
+
+	req := &pluginpb.CodeGeneratorRequest{
+		FileToGenerate: []string{file.Path()},
+		Parameter:      proto.String("paths=source_relative"),
+	}
+	seen := map[string]bool{}
+	var add func(fd protoreflect.FileDescriptor)
+	add = func(fd protoreflect.FileDescriptor) {
+		if seen[fd.Path()] {
+			return
+		}
+		seen[fd.Path()] = true
+		imports := fd.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			add(imports.Get(i).FileDescriptor)
+		}
+		req.ProtoFile = append(req.ProtoFile, protodesc.ToFileDescriptorProto(fd))
+	}
+	add(file)
+
+	gen, err := protogen.Options{}.New(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range gen.Files {
+		if f.Generate {
+			if err := generateFile(gen, f); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	resp := gen.Response()
+	if resp.Error != nil {
+		t.Fatalf("plugin error: %s", resp.GetError())
+	}
+	if len(resp.File) != 1 {
+		t.Fatalf("generated %d files, want 1", len(resp.File))
+	}
+	return resp.File[0].GetContent(), nil
+}
+
+// TestGenerateUserExample keeps the generated code of the examples in sync with the plugin.
+// Run with -update to regenerate it.
+func TestGenerateUserExample(t *testing.T) {
+	const golden = "../../../examples/actors/user_example_spawn.pb.go"
+
+	got := generate(t, actors.File_user_example_proto)
+	if *update {
+		if err := os.WriteFile(golden, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("generated code differs from %s, run go test -update to regenerate it:\n%s", golden, got)
+	}
+}
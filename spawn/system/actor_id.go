@@ -0,0 +1,90 @@
+package system
+
+import (
+	"fmt"
+
+	protocol "github.com/eigr/spawn-go-sdk/spawn/eigr/functions/protocol/actors"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// ActorIdFromMessage returns the value of the field of msg annotated with the
+// (eigr.functions.protocol.actors.actor_id) = true option, used as the name of
+// the Unnamed actor instance a request is routed to.
+// Only string and integer fields name instances, it returns false when msg has no such field,
+// when the field is of another kind or when it is empty.
+func ActorIdFromMessage(msg proto.Message) (string, bool) {
+	if msg == nil {
+		return "", false
+	}
+
+	m := msg.ProtoReflect()
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		if !isActorIdField(field) {
+			continue
+		}
+
+		if field.IsList() || field.IsMap() || !isActorIdKind(field.Kind()) || !m.Has(field) {
+			return "", false
+		}
+
+		value := fmt.Sprint(m.Get(field).Interface())
+		return value, value != ""
+	}
+
+	return "", false
+}
+
+// isActorIdKind reports whether fields of the kind can name an actor instance.
+func isActorIdKind(kind protoreflect.Kind) bool {
+	switch kind {
+	case protoreflect.StringKind,
+		protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return true
+	default:
+		return false
+	}
+}
+
+// isActorIdField reports whether the field is annotated with (actor_id) = true.
+func isActorIdField(field protoreflect.FieldDescriptor) bool {
+	options := field.Options()
+	if options == nil {
+		return false
+	}
+
+	if proto.HasExtension(options, protocol.E_ActorId) {
+		annotated, _ := proto.GetExtension(options, protocol.E_ActorId).(bool)
+		return annotated
+	}
+
+	// Descriptors built without the extension registered keep it as an unknown field
+	unknown := options.ProtoReflect().GetUnknown()
+	for len(unknown) > 0 {
+		number, wireType, n := protowire.ConsumeTag(unknown)
+		if n < 0 {
+			return false
+		}
+		unknown = unknown[n:]
+
+		if number == protocol.E_ActorId.TypeDescriptor().Number() && wireType == protowire.VarintType {
+			value, n := protowire.ConsumeVarint(unknown)
+			return n >= 0 && value != 0
+		}
+
+		n = protowire.ConsumeFieldValue(number, wireType, unknown)
+		if n < 0 {
+			return false
+		}
+		unknown = unknown[n:]
+	}
+
+	return false
+}
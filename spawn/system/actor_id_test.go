@@ -0,0 +1,166 @@
+package system
+
+import (
+	"fmt"
+	"testing"
+
+	protocol "github.com/eigr/spawn-go-sdk/spawn/eigr/functions/protocol/actors"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// registeredActorId annotates a field with the (actor_id) extension as registered by package protocol.
+func registeredActorId(annotated bool) *descriptorpb.FieldOptions {
+	options := &descriptorpb.FieldOptions{}
+	proto.SetExtension(options, protocol.E_ActorId, annotated)
+	return options
+}
+
+// unknownActorId annotates a field with the (actor_id) option kept as an unknown field,
+// as in descriptors built without the extension registered, after an unrelated unknown field.
+func unknownActorId(value uint64) *descriptorpb.FieldOptions {
+	var unknown []byte
+	unknown = protowire.AppendTag(unknown, 50000, protowire.BytesType)
+	unknown = protowire.AppendString(unknown, "unrelated")
+	unknown = protowire.AppendTag(unknown, protocol.E_ActorId.TypeDescriptor().Number(), protowire.VarintType)
+	unknown = protowire.AppendVarint(unknown, value)
+
+	options := &descriptorpb.FieldOptions{}
+	options.ProtoReflect().SetUnknown(unknown)
+	return options
+}
+
+// newActorIdMessage returns a dynamic message whose only field is of the given type and options.
+func newActorIdMessage(t *testing.T, name string, fieldType descriptorpb.FieldDescriptorProto_Type, options *descriptorpb.FieldOptions) *dynamicpb.Message {
+	t.Helper()
+
+	field := &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String("id"),
+		JsonName: proto.String("id"),
+		Number:   proto.Int32(1),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Type:     fieldType.Enum(),
+		Options:  options,
+	}
+	if fieldType == descriptorpb.FieldDescriptorProto_TYPE_ENUM {
+		field.TypeName = proto.String(".test.Color")
+	}
+
+	file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String(name + ".proto"),
+		Package: proto.String("test"),
+		Syntax:  proto.String("proto3"),
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name:  proto.String("Color"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{{Name: proto.String("RED"), Number: proto.Int32(0)}, {Name: proto.String("BLUE"), Number: proto.Int32(1)}},
+		}},
+		MessageType: []*descriptorpb.DescriptorProto{{Name: proto.String("Request"), Field: []*descriptorpb.FieldDescriptorProto{field}}},
+	}, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dynamicpb.NewMessage(file.Messages().Get(0))
+}
+
+func TestActorIdFromMessage(t *testing.T) {
+	tests := []struct {
+		name      string
+		fieldType descriptorpb.FieldDescriptorProto_Type
+		options   *descriptorpb.FieldOptions
+		value     protoreflect.Value // Left unset when invalid
+		want      string
+		wantOk    bool
+	}{
+		{
+			name:      "registered extension",
+			fieldType: descriptorpb.FieldDescriptorProto_TYPE_STRING,
+			options:   registeredActorId(true),
+			value:     protoreflect.ValueOfString("user-42"),
+			want:      "user-42",
+			wantOk:    true,
+		},
+		{
+			name:      "registered extension set to false",
+			fieldType: descriptorpb.FieldDescriptorProto_TYPE_STRING,
+			options:   registeredActorId(false),
+			value:     protoreflect.ValueOfString("user-42"),
+		},
+		{
+			name:      "unknown field",
+			fieldType: descriptorpb.FieldDescriptorProto_TYPE_STRING,
+			options:   unknownActorId(1),
+			value:     protoreflect.ValueOfString("user-42"),
+			want:      "user-42",
+			wantOk:    true,
+		},
+		{
+			name:      "unknown field set to false",
+			fieldType: descriptorpb.FieldDescriptorProto_TYPE_STRING,
+			options:   unknownActorId(0),
+			value:     protoreflect.ValueOfString("user-42"),
+		},
+		{
+			name:      "not annotated",
+			fieldType: descriptorpb.FieldDescriptorProto_TYPE_STRING,
+			value:     protoreflect.ValueOfString("user-42"),
+		},
+		{
+			name:      "empty string",
+			fieldType: descriptorpb.FieldDescriptorProto_TYPE_STRING,
+			options:   registeredActorId(true),
+		},
+		{
+			name:      "int64",
+			fieldType: descriptorpb.FieldDescriptorProto_TYPE_INT64,
+			options:   registeredActorId(true),
+			value:     protoreflect.ValueOfInt64(-42),
+			want:      "-42",
+			wantOk:    true,
+		},
+		{
+			name:      "fixed32",
+			fieldType: descriptorpb.FieldDescriptorProto_TYPE_FIXED32,
+			options:   unknownActorId(1),
+			value:     protoreflect.ValueOfUint32(7),
+			want:      "7",
+			wantOk:    true,
+		},
+		{
+			name:      "bytes",
+			fieldType: descriptorpb.FieldDescriptorProto_TYPE_BYTES,
+			options:   registeredActorId(true),
+			value:     protoreflect.ValueOfBytes([]byte("ab")),
+		},
+		{
+			name:      "bool",
+			fieldType: descriptorpb.FieldDescriptorProto_TYPE_BOOL,
+			options:   registeredActorId(true),
+			value:     protoreflect.ValueOfBool(true),
+		},
+		{
+			name:      "enum",
+			fieldType: descriptorpb.FieldDescriptorProto_TYPE_ENUM,
+			options:   registeredActorId(true),
+			value:     protoreflect.ValueOfEnum(1),
+		},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := newActorIdMessage(t, fmt.Sprintf("actor_id_%d", i), tt.fieldType, tt.options)
+			if tt.value.IsValid() {
+				msg.Set(msg.Descriptor().Fields().Get(0), tt.value)
+			}
+
+			got, ok := ActorIdFromMessage(msg)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("ActorIdFromMessage() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
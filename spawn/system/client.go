@@ -0,0 +1,46 @@
+package system
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// InvokeTyped invokes an action of an actor of the system and returns the response as Resp.
+// An empty id targets the registered actor itself, otherwise its Unnamed instance named id.
+// Options{"route_by_actor_id": true} with an empty id routes by the (actor_id) field of the request instead,
+// generated clients set it for the requests that have such a field.
+// A response without payload, e.g. from an async invocation, is returned as the zero Resp.
+// It is the building block of the clients generated by protoc-gen-spawn-go.
+func InvokeTyped[Resp proto.Message](ctx context.Context, s *System, actor string, id string, action string, request proto.Message, options ...Options) (Resp, error) {
+	var zero Resp
+
+	invokeOptions := Options{}
+	for _, opts := range options {
+		for key, value := range opts {
+			invokeOptions[key] = value
+		}
+	}
+
+	target := actor
+	if id != "" {
+		target = id
+		invokeOptions["parent"] = actor
+	}
+
+	resp, err := s.InvokeContext(ctx, s.name, target, action, request, invokeOptions)
+	if err != nil {
+		return zero, err
+	}
+	if resp == nil {
+		return zero, nil
+	}
+
+	typed, ok := resp.(Resp)
+	if !ok {
+		return zero, fmt.Errorf("action %s of actor %s returned %T, expected %T", action, actor, resp, zero)
+	}
+
+	return typed, nil
+}
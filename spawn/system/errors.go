@@ -0,0 +1,148 @@
+package system
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/eigr/spawn-go-sdk/spawn/actors"
+	protocol "github.com/eigr/spawn-go-sdk/spawn/eigr/functions/protocol/actors"
+
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// Errors returned by invocations, use errors.As with *actors.Error to get the code, message and detail.
+var (
+	ErrActorNotFound  = errors.New("actor not found")
+	ErrActionNotFound = errors.New("action not found")
+	ErrInvalidPayload = errors.New("invalid payload")
+	ErrInvalidState   = errors.New("invalid actor state")
+	ErrHandlerFailed  = errors.New("action handler failed")
+)
+
+// errorDomain identifies the google.rpc.Status payloads that carry an actors.Error.
+const errorDomain = "spawn.eigr.io"
+
+// remoteError is an actors.Error reported by the ActorHost of the invoked actor.
+type remoteError struct {
+	sentinel error
+	err      *actors.Error
+}
+
+func newRemoteError(err *actors.Error) *remoteError {
+	switch err.Code {
+	case actors.CodeActorNotFound:
+		return &remoteError{sentinel: ErrActorNotFound, err: err}
+	case actors.CodeActionNotFound:
+		return &remoteError{sentinel: ErrActionNotFound, err: err}
+	case actors.CodeInvalidPayload:
+		return &remoteError{sentinel: ErrInvalidPayload, err: err}
+	case actors.CodeInvalidState:
+		return &remoteError{sentinel: ErrInvalidState, err: err}
+	default:
+		return &remoteError{sentinel: ErrHandlerFailed, err: err}
+	}
+}
+
+func (e *remoteError) Error() string {
+	return e.err.Error()
+}
+
+func (e *remoteError) Unwrap() []error {
+	return []error{e.sentinel, e.err}
+}
+
+// statusError converts a failed RequestStatus returned by the proxy.
+func statusError(requestStatus *protocol.RequestStatus) error {
+	switch requestStatus.GetStatus() {
+	case protocol.Status_ACTOR_NOT_FOUND:
+		return fmt.Errorf("%w: %s", ErrActorNotFound, requestStatus.GetMessage())
+	case protocol.Status_ERROR:
+		return fmt.Errorf("%w: %s", ErrHandlerFailed, requestStatus.GetMessage())
+	default:
+		return fmt.Errorf("actor invocation failed: %s", requestStatus)
+	}
+}
+
+// handlerError returns the actors.Error reported to the caller for an error returned by a handler.
+func handlerError(err error) *actors.Error {
+	var actorErr *actors.Error
+	if errors.As(err, &actorErr) {
+		return actorErr
+	}
+	return actors.NewError(actors.CodeHandlerFailed, err.Error())
+}
+
+// encodeError encodes err as a google.rpc.Status whose ErrorInfo holds the code.
+func encodeError(err *actors.Error) (*anypb.Any, error) {
+	info, e := anypb.New(&errdetails.ErrorInfo{Reason: err.Code, Domain: errorDomain})
+	if e != nil {
+		return nil, e
+	}
+
+	st := &status.Status{
+		Code:    int32(grpcCode(err.Code)),
+		Message: err.Message,
+		Details: []*anypb.Any{info},
+	}
+
+	if err.Detail != nil {
+		detail, e := anypb.New(err.Detail)
+		if e != nil {
+			return nil, fmt.Errorf("failed to encode error detail: %w", e)
+		}
+		st.Details = append(st.Details, detail)
+	}
+
+	return anypb.New(st)
+}
+
+// decodeError returns the actors.Error carried by a response payload, if any.
+// Details of unknown types are handed over as the *anypb.Any itself.
+func decodeError(payload *anypb.Any) (*actors.Error, bool) {
+	st := &status.Status{}
+	if !payload.MessageIs(st) || payload.UnmarshalTo(st) != nil {
+		return nil, false
+	}
+
+	var err *actors.Error
+	var detail *anypb.Any
+	for _, d := range st.GetDetails() {
+		info := &errdetails.ErrorInfo{}
+		if err == nil && d.MessageIs(info) && d.UnmarshalTo(info) == nil && info.GetDomain() == errorDomain {
+			err = actors.NewError(info.GetReason(), st.GetMessage())
+		} else if detail == nil {
+			detail = d
+		}
+	}
+	if err == nil {
+		return nil, false
+	}
+
+	if detail != nil {
+		if msg, e := unmarshalAny(detail); e == nil {
+			err.Detail = msg
+		} else {
+			err.Detail = detail
+		}
+	}
+
+	return err, true
+}
+
+func grpcCode(errorCode string) code.Code {
+	switch errorCode {
+	case actors.CodeActorNotFound:
+		return code.Code_NOT_FOUND
+	case actors.CodeActionNotFound:
+		return code.Code_UNIMPLEMENTED
+	case actors.CodeInvalidPayload:
+		return code.Code_INVALID_ARGUMENT
+	case actors.CodeInvalidState:
+		return code.Code_FAILED_PRECONDITION
+	default:
+		return code.Code_UNKNOWN
+	}
+}
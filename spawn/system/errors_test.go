@@ -0,0 +1,134 @@
+package system
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/eigr/spawn-go-sdk/spawn/actors"
+
+	"google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestErrorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        *actors.Error
+		wantDetail proto.Message
+	}{
+		{
+			name: "code and message",
+			err:  actors.NewError("INVALID_NAME", "the new name must not be empty"),
+		},
+		{
+			name:       "registered detail",
+			err:        actors.NewError("INVALID_NAME", "too long").WithDetail(wrapperspb.String("name")),
+			wantDetail: wrapperspb.String("name"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := encodeError(tt.err)
+			if err != nil {
+				t.Fatalf("encodeError() error = %v", err)
+			}
+
+			got, ok := decodeError(payload)
+			if !ok {
+				t.Fatal("decodeError() did not recognize the encoded error")
+			}
+			if got.Code != tt.err.Code || got.Message != tt.err.Message {
+				t.Errorf("decodeError() = %s, want %s", got, tt.err)
+			}
+			if tt.wantDetail == nil {
+				if got.Detail != nil {
+					t.Errorf("Detail = %v, want nil", got.Detail)
+				}
+				return
+			}
+			if !proto.Equal(got.Detail, tt.wantDetail) {
+				t.Errorf("Detail = %v (%T), want %v (%T)", got.Detail, got.Detail, tt.wantDetail, tt.wantDetail)
+			}
+		})
+	}
+}
+
+// TestDecodeErrorUnregisteredDetail decodes an error whose detail type is only known to the ActorHost.
+func TestDecodeErrorUnregisteredDetail(t *testing.T) {
+	payload, err := encodeError(actors.NewError("INVALID_NAME", "too long").WithDetail(wrapperspb.String("name")))
+	if err != nil {
+		t.Fatalf("encodeError() error = %v", err)
+	}
+
+	st := &status.Status{}
+	if err := payload.UnmarshalTo(st); err != nil {
+		t.Fatal(err)
+	}
+	unregistered := st.Details[1]
+	unregistered.TypeUrl = "type.googleapis.com/test.Unregistered"
+	if payload, err = anypb.New(st); err != nil {
+		t.Fatal(err)
+	}
+
+	got, ok := decodeError(payload)
+	if !ok {
+		t.Fatal("decodeError() did not recognize the encoded error")
+	}
+	detail, ok := got.Detail.(*anypb.Any)
+	if !ok || !proto.Equal(detail, unregistered) {
+		t.Errorf("Detail = %v (%T), want the *anypb.Any %v", got.Detail, got.Detail, unregistered)
+	}
+}
+
+func TestDecodeErrorIgnoresOtherPayloads(t *testing.T) {
+	payload, err := anypb.New(wrapperspb.String("response"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, ok := decodeError(payload); ok {
+		t.Errorf("decodeError() = %v, want no error", got)
+	}
+}
+
+func TestRemoteErrorSentinels(t *testing.T) {
+	tests := []struct {
+		code     string
+		sentinel error
+	}{
+		{code: actors.CodeActorNotFound, sentinel: ErrActorNotFound},
+		{code: actors.CodeActionNotFound, sentinel: ErrActionNotFound},
+		{code: actors.CodeInvalidPayload, sentinel: ErrInvalidPayload},
+		{code: actors.CodeInvalidState, sentinel: ErrInvalidState},
+		{code: actors.CodeHandlerFailed, sentinel: ErrHandlerFailed},
+		{code: "INVALID_NAME", sentinel: ErrHandlerFailed},
+	}
+	sentinels := []error{ErrActorNotFound, ErrActionNotFound, ErrInvalidPayload, ErrInvalidState, ErrHandlerFailed}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			payload, err := encodeError(actors.NewError(tt.code, "failed"))
+			if err != nil {
+				t.Fatalf("encodeError() error = %v", err)
+			}
+			decoded, ok := decodeError(payload)
+			if !ok {
+				t.Fatal("decodeError() did not recognize the encoded error")
+			}
+
+			err = newRemoteError(decoded)
+			for _, sentinel := range sentinels {
+				if got, want := errors.Is(err, sentinel), sentinel == tt.sentinel; got != want {
+					t.Errorf("errors.Is(err, %q) = %v, want %v", sentinel, got, want)
+				}
+			}
+
+			var actorErr *actors.Error
+			if !errors.As(err, &actorErr) || actorErr.Code != tt.code {
+				t.Errorf("errors.As(err, *actors.Error) = %v, want code %s", actorErr, tt.code)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package system
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+)
+
+// InvocationIdMetadataKey is the invocation metadata key carrying the id that identifies
+// an invocation in the logs of both the caller and the ActorHost of the invoked actor.
+const InvocationIdMetadataKey = "spawn-invocation-id"
+
+// Attribute keys of the structured logs of the system.
+const (
+	systemKey       = "system"
+	actorKey        = "actor"
+	actionKey       = "action"
+	invocationIdKey = "invocation_id"
+	durationKey     = "duration"
+	errorKey        = "error"
+)
+
+// WithLogger sets the logger of the system. Nothing is logged by default.
+// Payloads and states are only logged at debug level.
+func (s *System) WithLogger(logger *slog.Logger) *System {
+	if logger == nil {
+		logger = slog.New(discardHandler{})
+	}
+	s.logger = logger.With(slog.String(systemKey, s.name))
+	return s
+}
+
+// invocationLogger returns the logger of a single invocation.
+func (s *System) invocationLogger(actor string, action string, invocationId string) *slog.Logger {
+	return s.logger.With(
+		slog.String(actorKey, actor),
+		slog.String(actionKey, action),
+		slog.String(invocationIdKey, invocationId),
+	)
+}
+
+// withInvocationId returns a copy of the metadata carrying a new invocation id, keeping one already present.
+func withInvocationId(metadata map[string]string) (map[string]string, string) {
+	if id, ok := metadata[InvocationIdMetadataKey]; ok {
+		return metadata, id
+	}
+
+	b := make([]byte, 8)
+	rand.Read(b)
+	id := hex.EncodeToString(b)
+
+	withId := make(map[string]string, len(metadata)+1)
+	for key, value := range metadata {
+		withId[key] = value
+	}
+	withId[InvocationIdMetadataKey] = id
+	return withId, id
+}
+
+// discardHandler is a slog.Handler that drops every record.
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
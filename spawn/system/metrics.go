@@ -0,0 +1,294 @@
+package system
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/eigr/spawn-go-sdk/spawn/actors"
+	protocol "github.com/eigr/spawn-go-sdk/spawn/eigr/functions/protocol/actors"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// MetricsPath is the route of the ActorHost serving the metrics enabled with ServeMetrics.
+const MetricsPath = "/metrics"
+
+// Values of the result label of the invocation and registration metrics.
+const (
+	resultOk    = "ok"
+	resultError = "error"
+)
+
+// unknownLabel replaces the actor and action label values of invocations of actors or actions that are not registered,
+// whose names are chosen by the callers.
+const unknownLabel = "unknown"
+
+// customCodeLabel replaces the code label value of errors whose codes are chosen by the handlers.
+const customCodeLabel = "custom"
+
+// sizeBuckets spans payloads and states from 64 bytes to 1 MiB.
+var sizeBuckets = prometheus.ExponentialBuckets(64, 4, 8)
+
+// metrics holds the Prometheus collectors of a system. A nil *metrics records nothing.
+type metrics struct {
+	clientInvocations   *prometheus.CounterVec
+	clientDuration      *prometheus.HistogramVec
+	clientPayloadSize   *prometheus.HistogramVec
+	hostInvocations     *prometheus.CounterVec
+	hostDuration        *prometheus.HistogramVec
+	hostPayloadSize     *prometheus.HistogramVec
+	hostStateSize       *prometheus.HistogramVec
+	handlerErrors       *prometheus.CounterVec
+	handlersInFlight    *prometheus.GaugeVec
+	registrationAttempt *prometheus.CounterVec
+}
+
+// WithMetrics records Prometheus metrics of the invocations, of the actions handled by the ActorHost
+// and of the registration into registerer. Systems sharing a registerer share their metrics,
+// which are told apart by the system label. Nothing is recorded by default.
+func (s *System) WithMetrics(registerer prometheus.Registerer) *System {
+	if registerer == nil {
+		registerer = prometheus.DefaultRegisterer
+	}
+
+	m, err := newMetrics(registerer, s.name)
+	if err != nil {
+		s.metricsErr = err
+		return s
+	}
+	s.metrics = m
+	return s
+}
+
+// ServeMetrics serves the metrics of gatherer on the MetricsPath route of the ActorHost.
+func (s *System) ServeMetrics(gatherer prometheus.Gatherer) *System {
+	if gatherer == nil {
+		gatherer = prometheus.DefaultGatherer
+	}
+	s.metricsHandler = promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{})
+	return s
+}
+
+func newMetrics(registerer prometheus.Registerer, system string) (*metrics, error) {
+	m := &metrics{
+		clientInvocations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "spawn",
+			Subsystem: "client",
+			Name:      "invocations_total",
+			Help:      "Invocations made by the system, by result.",
+		}, []string{"system", "actor", "action", "result"}),
+		clientDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "spawn",
+			Subsystem: "client",
+			Name:      "invocation_duration_seconds",
+			Help:      "Duration of the invocations made by the system, retries included.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"system", "actor", "action"}),
+		clientPayloadSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "spawn",
+			Subsystem: "client",
+			Name:      "payload_size_bytes",
+			Help:      "Size of the payloads sent by the invocations of the system.",
+			Buckets:   sizeBuckets,
+		}, []string{"system", "actor", "action"}),
+		hostInvocations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "spawn",
+			Subsystem: "host",
+			Name:      "invocations_total",
+			Help:      "Invocations handled by the ActorHost, by result.",
+		}, []string{"system", "actor", "action", "result"}),
+		hostDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "spawn",
+			Subsystem: "host",
+			Name:      "invocation_duration_seconds",
+			Help:      "Duration of the invocations handled by the ActorHost.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"system", "actor", "action"}),
+		hostPayloadSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "spawn",
+			Subsystem: "host",
+			Name:      "payload_size_bytes",
+			Help:      "Size of the payloads received by the ActorHost.",
+			Buckets:   sizeBuckets,
+		}, []string{"system", "actor", "action"}),
+		hostStateSize: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "spawn",
+			Subsystem: "host",
+			Name:      "state_size_bytes",
+			Help:      "Size of the states returned to the proxy by the ActorHost.",
+			Buckets:   sizeBuckets,
+		}, []string{"system", "actor"}),
+		handlerErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "spawn",
+			Subsystem: "host",
+			Name:      "handler_errors_total",
+			Help:      "Errors reported to the callers by the ActorHost, by code.",
+		}, []string{"system", "actor", "action", "code"}),
+		handlersInFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "spawn",
+			Subsystem: "host",
+			Name:      "handlers_in_flight",
+			Help:      "Action handlers currently running in the ActorHost.",
+		}, []string{"system", "actor"}),
+		registrationAttempt: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "spawn",
+			Name:      "registration_attempts_total",
+			Help:      "Attempts to register the actors of the system with the proxy, by result.",
+		}, []string{"system", "result"}),
+	}
+
+	var errs []error
+	m.clientInvocations = register(registerer, m.clientInvocations, &errs)
+	m.clientDuration = register(registerer, m.clientDuration, &errs)
+	m.clientPayloadSize = register(registerer, m.clientPayloadSize, &errs)
+	m.hostInvocations = register(registerer, m.hostInvocations, &errs)
+	m.hostDuration = register(registerer, m.hostDuration, &errs)
+	m.hostPayloadSize = register(registerer, m.hostPayloadSize, &errs)
+	m.hostStateSize = register(registerer, m.hostStateSize, &errs)
+	m.handlerErrors = register(registerer, m.handlerErrors, &errs)
+	m.handlersInFlight = register(registerer, m.handlersInFlight, &errs)
+	m.registrationAttempt = register(registerer, m.registrationAttempt, &errs)
+
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("failed to register metrics: %w", errors.Join(errs...))
+	}
+
+	// Every series recorded by this system carries its name
+	labels := prometheus.Labels{"system": system}
+	m.clientInvocations = m.clientInvocations.MustCurryWith(labels)
+	m.clientDuration = m.clientDuration.MustCurryWith(labels).(*prometheus.HistogramVec)
+	m.clientPayloadSize = m.clientPayloadSize.MustCurryWith(labels).(*prometheus.HistogramVec)
+	m.hostInvocations = m.hostInvocations.MustCurryWith(labels)
+	m.hostDuration = m.hostDuration.MustCurryWith(labels).(*prometheus.HistogramVec)
+	m.hostPayloadSize = m.hostPayloadSize.MustCurryWith(labels).(*prometheus.HistogramVec)
+	m.hostStateSize = m.hostStateSize.MustCurryWith(labels).(*prometheus.HistogramVec)
+	m.handlerErrors = m.handlerErrors.MustCurryWith(labels)
+	m.handlersInFlight = m.handlersInFlight.MustCurryWith(labels)
+	m.registrationAttempt = m.registrationAttempt.MustCurryWith(labels)
+	return m, nil
+}
+
+// register registers collector, reusing the collector already registered by another system.
+func register[C prometheus.Collector](registerer prometheus.Registerer, collector C, errs *[]error) C {
+	err := registerer.Register(collector)
+	if err == nil {
+		return collector
+	}
+
+	var already prometheus.AlreadyRegisteredError
+	if errors.As(err, &already) {
+		if existing, ok := already.ExistingCollector.(C); ok {
+			return existing
+		}
+	}
+	*errs = append(*errs, err)
+	return collector
+}
+
+// observeInvocation records an invocation made by the system.
+func (m *metrics) observeInvocation(actor string, action string, payloadSize int, duration time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	m.clientInvocations.WithLabelValues(actor, action, result(err)).Inc()
+	m.clientDuration.WithLabelValues(actor, action).Observe(duration.Seconds())
+	m.clientPayloadSize.WithLabelValues(actor, action).Observe(float64(payloadSize))
+}
+
+// observeHostInvocation records an invocation handled by the ActorHost.
+func (m *metrics) observeHostInvocation(actor string, action string, duration time.Duration, failed bool) {
+	if m == nil {
+		return
+	}
+	r := resultOk
+	if failed {
+		r = resultError
+	}
+	m.hostInvocations.WithLabelValues(actor, action, r).Inc()
+	m.hostDuration.WithLabelValues(actor, action).Observe(duration.Seconds())
+}
+
+// observeHostPayload records the size of a payload received by the ActorHost.
+func (m *metrics) observeHostPayload(actor string, action string, size int) {
+	if m == nil {
+		return
+	}
+	m.hostPayloadSize.WithLabelValues(actor, action).Observe(float64(size))
+}
+
+// observeState records the size of a state returned by the ActorHost.
+func (m *metrics) observeState(actor string, size int) {
+	if m == nil {
+		return
+	}
+	m.hostStateSize.WithLabelValues(actor).Observe(float64(size))
+}
+
+// handlerError records an error reported to a caller by the ActorHost.
+func (m *metrics) handlerError(actor string, action string, code string) {
+	if m == nil {
+		return
+	}
+	m.handlerErrors.WithLabelValues(actor, action, codeLabel(code)).Inc()
+}
+
+// handlerStarted records a running action handler, the returned function records its end.
+func (m *metrics) handlerStarted(actor string) func() {
+	if m == nil {
+		return func() {}
+	}
+	gauge := m.handlersInFlight.WithLabelValues(actor)
+	gauge.Inc()
+	return gauge.Dec
+}
+
+// registrationAttempted records an attempt to register the actors with the proxy.
+func (m *metrics) registrationAttempted(err error) {
+	if m == nil {
+		return
+	}
+	m.registrationAttempt.WithLabelValues(result(err)).Inc()
+}
+
+// metricsLabels returns the actor and action labels of an invocation made by the system that failed with err.
+func metricsLabels(id *protocol.ActorId, action string, err error) (string, string) {
+	switch {
+	case errors.Is(err, ErrActorNotFound):
+		return unknownLabel, unknownLabel
+	case errors.Is(err, ErrActionNotFound):
+		return registeredActorName(id), unknownLabel
+	default:
+		return registeredActorName(id), action
+	}
+}
+
+// hostMetricsLabels returns the actor and action labels of an invocation handled by the ActorHost.
+func (s *System) hostMetricsLabels(actorInvocation *protocol.ActorInvocation) (string, string) {
+	actor, ok := s.lookupActor(actorInvocation.GetActor())
+	if !ok {
+		return unknownLabel, unknownLabel
+	}
+	if _, ok := actor.Handler(actorInvocation.GetActionName()); !ok {
+		return actor.Name, unknownLabel
+	}
+	return actor.Name, actorInvocation.GetActionName()
+}
+
+// codeLabel returns the code label of an error, bounding the codes chosen by the handlers to customCodeLabel.
+func codeLabel(code string) string {
+	switch code {
+	case actors.CodeActorNotFound, actors.CodeActionNotFound, actors.CodeInvalidPayload, actors.CodeInvalidState, actors.CodeHandlerFailed:
+		return code
+	default:
+		return customCodeLabel
+	}
+}
+
+func result(err error) string {
+	if err != nil {
+		return resultError
+	}
+	return resultOk
+}
@@ -0,0 +1,186 @@
+package system
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/eigr/spawn-go-sdk/spawn/actors"
+	protocol "github.com/eigr/spawn-go-sdk/spawn/eigr/functions/protocol/actors"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestMetricsLabels(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         *protocol.ActorId
+		action     string
+		err        error
+		wantActor  string
+		wantAction string
+	}{
+		{
+			name:       "named actor",
+			id:         &protocol.ActorId{Name: "A"},
+			action:     "act",
+			wantActor:  "A",
+			wantAction: "act",
+		},
+		{
+			name:       "unnamed instance",
+			id:         &protocol.ActorId{Name: "user-42", Parent: "UserActor"},
+			action:     "act",
+			err:        fmt.Errorf("invoke failed: %w", ErrHandlerFailed),
+			wantActor:  "UserActor",
+			wantAction: "act",
+		},
+		{
+			name:       "actor not found",
+			id:         &protocol.ActorId{Name: "user-42", Parent: "UserActor"},
+			action:     "act",
+			err:        fmt.Errorf("invoke failed: %w", ErrActorNotFound),
+			wantActor:  unknownLabel,
+			wantAction: unknownLabel,
+		},
+		{
+			name:       "action not found",
+			id:         &protocol.ActorId{Name: "user-42", Parent: "UserActor"},
+			action:     "act",
+			err:        fmt.Errorf("invoke failed: %w", ErrActionNotFound),
+			wantActor:  "UserActor",
+			wantAction: unknownLabel,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actor, action := metricsLabels(tt.id, tt.action, tt.err)
+			if actor != tt.wantActor || action != tt.wantAction {
+				t.Errorf("metricsLabels() = %q, %q, want %q, %q", actor, action, tt.wantActor, tt.wantAction)
+			}
+		})
+	}
+}
+
+func TestHostMetricsLabels(t *testing.T) {
+	s := NewSystem("test-system")
+	for _, config := range []actors.ActorConfig{{Name: "A", Kind: actors.Named}, {Name: "UserActor", Kind: actors.Unnamed}} {
+		actor := actors.ActorOf(config)
+		actor.AddAction("act", func(ctx *actors.ActorContext, payload proto.Message) (actors.Value, error) {
+			return actors.Value{}, nil
+		})
+		s.RegisterActor(actor)
+	}
+
+	tests := []struct {
+		name       string
+		invocation *protocol.ActorInvocation
+		wantActor  string
+		wantAction string
+	}{
+		{
+			name:       "named actor",
+			invocation: &protocol.ActorInvocation{Actor: &protocol.ActorId{Name: "A"}, ActionName: "act"},
+			wantActor:  "A",
+			wantAction: "act",
+		},
+		{
+			name:       "unnamed instance",
+			invocation: &protocol.ActorInvocation{Actor: &protocol.ActorId{Name: "user-42", Parent: "UserActor"}, ActionName: "act"},
+			wantActor:  "UserActor",
+			wantAction: "act",
+		},
+		{
+			name:       "unknown action",
+			invocation: &protocol.ActorInvocation{Actor: &protocol.ActorId{Name: "A"}, ActionName: "random-action"},
+			wantActor:  "A",
+			wantAction: unknownLabel,
+		},
+		{
+			name:       "unknown actor",
+			invocation: &protocol.ActorInvocation{Actor: &protocol.ActorId{Name: "random-actor"}, ActionName: "act"},
+			wantActor:  unknownLabel,
+			wantAction: unknownLabel,
+		},
+		{
+			name:       "no actor",
+			invocation: &protocol.ActorInvocation{ActionName: "act"},
+			wantActor:  unknownLabel,
+			wantAction: unknownLabel,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actor, action := s.hostMetricsLabels(tt.invocation)
+			if actor != tt.wantActor || action != tt.wantAction {
+				t.Errorf("hostMetricsLabels() = %q, %q, want %q, %q", actor, action, tt.wantActor, tt.wantAction)
+			}
+		})
+	}
+}
+
+func TestMetricsSharedRegisterer(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	first := NewSystem("first").WithMetrics(registry)
+	second := NewSystem("second").WithMetrics(registry)
+	if first.metricsErr != nil || second.metricsErr != nil {
+		t.Fatalf("WithMetrics() errors = %v, %v", first.metricsErr, second.metricsErr)
+	}
+
+	first.metrics.handlerError("A", "act", actors.CodeInvalidPayload)
+	first.metrics.handlerError("A", "act", "USER_NOT_FOUND")
+	second.metrics.handlerError("A", "act", "OUT_OF_STOCK")
+
+	want := `
+# HELP spawn_host_handler_errors_total Errors reported to the callers by the ActorHost, by code.
+# TYPE spawn_host_handler_errors_total counter
+spawn_host_handler_errors_total{action="act",actor="A",code="INVALID_PAYLOAD",system="first"} 1
+spawn_host_handler_errors_total{action="act",actor="A",code="custom",system="first"} 1
+spawn_host_handler_errors_total{action="act",actor="A",code="custom",system="second"} 1
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(want), "spawn_host_handler_errors_total"); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMetricsConflictingRegisterer(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "spawn_host_handler_errors_total",
+		Help: "A collector with other labels.",
+	}))
+
+	s := NewSystem("test-system").WithMetrics(registry)
+	if s.metricsErr == nil {
+		t.Error("WithMetrics() error = nil, want the conflicting collector to be reported")
+	}
+}
+
+func TestMetricsRoute(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	s := NewSystem("test-system").WithMetrics(registry).ServeMetrics(registry)
+	mux := s.newServeMux()
+
+	body, err := proto.Marshal(&protocol.ActorInvocation{Actor: &protocol.ActorId{Name: "random-actor"}, ActionName: "act"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/v1/actors/actions", bytes.NewReader(body)))
+
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, MetricsPath, nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", recorder.Code, http.StatusOK)
+	}
+	want := `spawn_host_handler_errors_total{action="unknown",actor="unknown",code="ACTOR_NOT_FOUND",system="test-system"} 1`
+	if !strings.Contains(recorder.Body.String(), want) {
+		t.Errorf("metrics do not contain %s:\n%s", want, recorder.Body)
+	}
+}
@@ -0,0 +1,103 @@
+package system
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"time"
+)
+
+// RetryPolicy controls how invocations that failed to reach an actor are retried.
+// By default only invocations made with Options{"idempotent": true} are retried.
+type RetryPolicy struct {
+	MaxAttempts        int                  // Total attempts including the first one, below 2 disables retries
+	InitialBackoff     time.Duration        // Wait before the first retry
+	MaxBackoff         time.Duration        // Upper bound of the wait between attempts, zero for no bound
+	Multiplier         float64              // Growth of the wait between attempts, below 1 keeps it constant
+	Jitter             float64              // Fraction of the wait that is randomized, between 0 and 1
+	RetryableStatuses  []int                // HTTP status codes of the proxy worth retrying
+	RetryOn            func(err error) bool // Decides for errors without a response, all are retried when nil
+	RetryNonIdempotent bool                 // Also retry invocations not marked as idempotent
+}
+
+// DefaultRetryPolicy retries idempotent invocations up to three times
+// on network errors and on proxy unavailability.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:       3,
+	InitialBackoff:    100 * time.Millisecond,
+	MaxBackoff:        2 * time.Second,
+	Multiplier:        2,
+	Jitter:            0.2,
+	RetryableStatuses: []int{502, 503, 504},
+}
+
+// NoRetry disables retries.
+var NoRetry = RetryPolicy{MaxAttempts: 1}
+
+// InvocationError is returned when an invocation could not be completed by the proxy.
+type InvocationError struct {
+	Actor      string
+	StatusCode int // HTTP status code of the last attempt, zero when no response was received
+	Attempts   int
+	Err        error
+}
+
+func (e *InvocationError) Error() string {
+	return fmt.Sprintf("invocation of actor %s failed after %d attempt(s): %v", e.Actor, e.Attempts, e.Err)
+}
+
+func (e *InvocationError) Unwrap() error {
+	return e.Err
+}
+
+func (p RetryPolicy) maxAttempts(idempotent bool) int {
+	if p.MaxAttempts < 2 || (!idempotent && !p.RetryNonIdempotent) {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// shouldRetry reports whether an attempt that failed with the given status code and error can be retried.
+func (p RetryPolicy) shouldRetry(ctx context.Context, statusCode int, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if statusCode != 0 {
+		return slices.Contains(p.RetryableStatuses, statusCode)
+	}
+	return p.RetryOn == nil || p.RetryOn(err)
+}
+
+// backoff returns the wait before the given retry, counting from 1.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	backoff := float64(p.InitialBackoff)
+	for i := 1; i < attempt && p.Multiplier > 1; i++ {
+		backoff *= p.Multiplier
+		if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+
+	if jitter := min(max(p.Jitter, 0), 1); jitter > 0 {
+		backoff -= backoff * jitter * rand.Float64()
+	}
+
+	return time.Duration(backoff)
+}
+
+// sleepContext waits for d unless ctx is done first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,165 @@
+package system
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBackoff(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, MaxBackoff: time.Second, Multiplier: 2}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: 100 * time.Millisecond},
+		{attempt: 2, want: 200 * time.Millisecond},
+		{attempt: 4, want: 800 * time.Millisecond},
+		{attempt: 5, want: time.Second},
+		{attempt: 100, want: time.Second},
+	}
+	for _, tt := range tests {
+		if got := policy.backoff(tt.attempt); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestBackoffJitter(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: 100 * time.Millisecond, Multiplier: 1, Jitter: 0.2}
+
+	for i := 0; i < 1000; i++ {
+		got := policy.backoff(1)
+		if got < 80*time.Millisecond || got > 100*time.Millisecond {
+			t.Fatalf("backoff(1) = %v, want between 80ms and 100ms", got)
+		}
+	}
+}
+
+// newRetryTestSystem returns a system whose proxy replies to every attempt with the given status codes in turn,
+// repeating the last one, along with the counter of attempts received.
+func newRetryTestSystem(t *testing.T, policy RetryPolicy, statuses ...int) (*System, *atomic.Int32) {
+	t.Helper()
+
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempt := int(attempts.Add(1))
+		status := statuses[min(attempt, len(statuses))-1]
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, err := strconv.Atoi(serverURL.Port())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewSystem("test-system").UseProxyPort(port).WithRetryPolicy(policy), &attempts
+}
+
+func TestInvokeActorRetries(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, RetryableStatuses: []int{503}}
+
+	tests := []struct {
+		name         string
+		statuses     []int
+		idempotent   bool
+		wantErr      bool
+		wantStatus   int
+		wantAttempts int
+	}{
+		{
+			name:         "succeeds after retries",
+			statuses:     []int{503, 503, 200},
+			idempotent:   true,
+			wantAttempts: 3,
+		},
+		{
+			name:         "gives up after max attempts",
+			statuses:     []int{503},
+			idempotent:   true,
+			wantErr:      true,
+			wantStatus:   503,
+			wantAttempts: 3,
+		},
+		{
+			name:         "non-idempotent is not retried",
+			statuses:     []int{503},
+			wantErr:      true,
+			wantStatus:   503,
+			wantAttempts: 1,
+		},
+		{
+			name:         "non-retryable status is not retried",
+			statuses:     []int{500},
+			idempotent:   true,
+			wantErr:      true,
+			wantStatus:   500,
+			wantAttempts: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, attempts := newRetryTestSystem(t, policy, tt.statuses...)
+
+			_, err := s.invokeActor(context.Background(), "A", nil, policy, tt.idempotent)
+
+			if got := int(attempts.Load()); got != tt.wantAttempts {
+				t.Errorf("proxy received %d attempts, want %d", got, tt.wantAttempts)
+			}
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("invokeActor() error = %v", err)
+				}
+				return
+			}
+
+			var invocationErr *InvocationError
+			if !errors.As(err, &invocationErr) {
+				t.Fatalf("invokeActor() error = %v, want an *InvocationError", err)
+			}
+			if invocationErr.Attempts != tt.wantAttempts {
+				t.Errorf("Attempts = %d, want %d", invocationErr.Attempts, tt.wantAttempts)
+			}
+			if invocationErr.StatusCode != tt.wantStatus {
+				t.Errorf("StatusCode = %d, want %d", invocationErr.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestInvokeActorCancelledDuringBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Hour, RetryableStatuses: []int{503}}
+	s, attempts := newRetryTestSystem(t, policy, 503)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := s.invokeActor(ctx, "A", nil, policy, true)
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("invokeActor() returned after %v, want it to stop waiting when the context is done", elapsed)
+	}
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("invokeActor() error = %v, want context.DeadlineExceeded", err)
+	}
+	var invocationErr *InvocationError
+	if !errors.As(err, &invocationErr) || invocationErr.Attempts != 1 {
+		t.Errorf("invokeActor() error = %v, want an *InvocationError after 1 attempt", err)
+	}
+	if got := attempts.Load(); got != 1 {
+		t.Errorf("proxy received %d attempts, want 1", got)
+	}
+}
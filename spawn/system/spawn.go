@@ -0,0 +1,116 @@
+package system
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/eigr/spawn-go-sdk/spawn/actors"
+	protocol "github.com/eigr/spawn-go-sdk/spawn/eigr/functions/protocol/actors"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// ActorRef references an Unnamed actor instance spawned in the system.
+type ActorRef struct {
+	system *System
+	id     actors.ActorId
+}
+
+// Id returns the identity of the referenced actor instance.
+func (r *ActorRef) Id() actors.ActorId {
+	return r.id
+}
+
+// Invoke calls an action of the referenced actor instance.
+func (r *ActorRef) Invoke(action string, request proto.Message, options Options) (proto.Message, error) {
+	return r.InvokeContext(context.Background(), action, request, options)
+}
+
+// InvokeContext is like Invoke, the invocation is bound to the deadline and cancellation of ctx.
+func (r *ActorRef) InvokeContext(ctx context.Context, action string, request proto.Message, options Options) (proto.Message, error) {
+	refOptions := make(Options, len(options)+1)
+	for key, value := range options {
+		refOptions[key] = value
+	}
+	refOptions["parent"] = r.id.Parent
+
+	return r.system.InvokeContext(ctx, r.id.System, r.id.Name, action, request, refOptions)
+}
+
+// Spawn creates Unnamed actor instances of the parent actor without sending them any message.
+// Instances are otherwise created lazily by the first invocation made with Options{"parent": ...}.
+func (s *System) Spawn(ctx context.Context, parent string, names ...string) ([]*ActorRef, error) {
+	if parent == "" {
+		return nil, fmt.Errorf("parent actor must not be empty")
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("at least one actor name is required to spawn %s instances", parent)
+	}
+
+	req := &protocol.SpawnRequest{}
+	refs := make([]*ActorRef, 0, len(names))
+	for _, name := range names {
+		if name == "" {
+			return nil, fmt.Errorf("actor name must not be empty when spawning %s instances", parent)
+		}
+
+		id := actors.ActorId{Name: name, System: s.name, Parent: parent}
+		req.Actors = append(req.Actors, &protocol.ActorId{Name: id.Name, System: id.System, Parent: id.Parent})
+		refs = append(refs, &ActorRef{system: s, id: id})
+	}
+
+	data, err := proto.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode SpawnRequest: %w", err)
+	}
+
+	responseBytes, err := s.spawnActors(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &protocol.SpawnResponse{}
+	if err := proto.Unmarshal(responseBytes, resp); err != nil {
+		return nil, fmt.Errorf("failed to parse SpawnResponse: %w", err)
+	}
+
+	if resp.Status.GetStatus() != protocol.Status_OK {
+		return nil, fmt.Errorf("failed to spawn %s instances: %s", parent, resp.Status)
+	}
+
+	return refs, nil
+}
+
+func (s *System) spawnActors(ctx context.Context, requestBytes []byte) ([]byte, error) {
+	url := fmt.Sprintf("%s:%d/api/v1/system/%s/actors/spawn", s.url, s.proxyPort, s.name)
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(requestBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "user-function-client/0.1.0")
+	req.Header.Set("Accept", "application/octet-stream")
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send SpawnRequest: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to spawn actors, status code: %d, error: %s", resp.StatusCode, string(body))
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read SpawnResponse: %w", err)
+	}
+
+	return respBody, nil
+}
@@ -0,0 +1,84 @@
+package system
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/eigr/spawn-go-sdk/spawn/actors"
+	protocol "github.com/eigr/spawn-go-sdk/spawn/eigr/functions/protocol/actors"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSpawn(t *testing.T) {
+	tests := []struct {
+		name       string
+		names      []string
+		httpStatus int
+		status     protocol.Status
+		wantErr    bool
+	}{
+		{name: "spawned", names: []string{"user-1", "user-2"}, httpStatus: http.StatusOK, status: protocol.Status_OK},
+		{name: "rejected by the proxy", names: []string{"user-1"}, httpStatus: http.StatusOK, status: protocol.Status_ERROR, wantErr: true},
+		{name: "proxy failure", names: []string{"user-1"}, httpStatus: http.StatusInternalServerError, wantErr: true},
+		{name: "no names", wantErr: true},
+		{name: "empty name", names: []string{"user-1", ""}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var received *protocol.SpawnRequest
+			s := newTestProxySystem(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/api/v1/system/test-system/actors/spawn" {
+					t.Errorf("path = %s, want the spawn route of test-system", r.URL.Path)
+				}
+				body, _ := io.ReadAll(r.Body)
+				received = &protocol.SpawnRequest{}
+				if err := proto.Unmarshal(body, received); err != nil {
+					t.Errorf("proxy received an invalid SpawnRequest: %v", err)
+				}
+
+				resp, _ := proto.Marshal(&protocol.SpawnResponse{Status: &protocol.RequestStatus{Status: tt.status}})
+				w.WriteHeader(tt.httpStatus)
+				w.Write(resp)
+			})
+
+			refs, err := s.Spawn(context.Background(), "UserActor", tt.names...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Spawn() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.httpStatus == 0 {
+				if received != nil {
+					t.Errorf("proxy received %v, want no request", received)
+				}
+				return
+			}
+
+			want := &protocol.SpawnRequest{}
+			for _, name := range tt.names {
+				want.Actors = append(want.Actors, &protocol.ActorId{Name: name, System: "test-system", Parent: "UserActor"})
+			}
+			if !proto.Equal(received, want) {
+				t.Errorf("proxy received %v, want %v", received, want)
+			}
+
+			if tt.wantErr {
+				if refs != nil {
+					t.Errorf("Spawn() = %v, want no refs", refs)
+				}
+				return
+			}
+			if len(refs) != len(tt.names) {
+				t.Fatalf("Spawn() returned %d refs, want %d", len(refs), len(tt.names))
+			}
+			for i, ref := range refs {
+				wantId := actors.ActorId{Name: tt.names[i], System: "test-system", Parent: "UserActor"}
+				if ref.Id() != wantId {
+					t.Errorf("refs[%d].Id() = %v, want %v", i, ref.Id(), wantId)
+				}
+			}
+		})
+	}
+}
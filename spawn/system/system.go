@@ -2,38 +2,77 @@ package system
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"runtime/debug"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/eigr/spawn-go-sdk/spawn/actors"
 	protocol "github.com/eigr/spawn-go-sdk/spawn/eigr/functions/protocol/actors"
 
 	"strings"
 
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/reflect/protoregistry"
 	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // System represents the Spawn system.
 type System struct {
-	actors     map[string]*actors.Actor
-	name       string
-	proxyPort  int
-	exposePort int
-	url        string
-	stopCh     chan struct{}
-	server     *http.Server
-	wg         sync.WaitGroup
+	actors             map[string]*actors.Actor
+	name               string
+	proxyPort          int
+	exposePort         int
+	url                string
+	propagatedMetadata []string
+	duplicateActors    []string
+	httpClient         *http.Client
+	retryPolicy        RetryPolicy
+	stopCh             chan struct{}
+	errCh              chan error
+	errMu              sync.Mutex // Guards errCh against sends once it is closed
+	errClosed          bool
+	logger             *slog.Logger
+	tracer             trace.Tracer
+	metrics            *metrics
+	metricsErr         error
+	metricsHandler     http.Handler
+	server             *http.Server
+	wg                 sync.WaitGroup
 }
 
+// DefaultRequestTimeout bounds every request made to the proxy unless another HTTP client is set.
+const DefaultRequestTimeout = 30 * time.Second
+
+// DeadlineMetadataKey is the invocation metadata key carrying the caller's deadline in RFC 3339 format.
+// The ActorHost applies it to the context handed to the handler.
+const DeadlineMetadataKey = "spawn-deadline"
+
+// DefaultPropagatedMetadata lists the invocation metadata keys that are propagated by default
+// from a handler to the invocations and side effects it issues.
+var DefaultPropagatedMetadata = []string{
+	"correlation-id",
+	"tenant-id",
+	"traceparent",
+	"tracestate",
+	"baggage",
+}
+
+// errorBufferSize is the number of errors kept for Errors() before new ones are dropped.
+const errorBufferSize = 16
+
 type invocationOptions map[string]interface{}
 
 // Creating an alias for InvocationOptions, now called Options
@@ -42,10 +81,16 @@ type Options = invocationOptions
 // NewSystem creates a new Spawn system.
 func NewSystem(name string) *System {
 	return &System{
-		actors: make(map[string]*actors.Actor),
-		name:   name,
-		url:    "http://localhost", // Default URL
-		stopCh: make(chan struct{}),
+		actors:             make(map[string]*actors.Actor),
+		name:               name,
+		url:                "http://localhost", // Default URL
+		propagatedMetadata: DefaultPropagatedMetadata,
+		httpClient:         &http.Client{Timeout: DefaultRequestTimeout},
+		retryPolicy:        DefaultRetryPolicy,
+		stopCh:             make(chan struct{}),
+		errCh:              make(chan error, errorBufferSize),
+		logger:             slog.New(discardHandler{}),
+		tracer:             noop.NewTracerProvider().Tracer(instrumentationName),
 	}
 }
 
@@ -61,8 +106,36 @@ func (s *System) ExposePort(port int) *System {
 	return s
 }
 
+// WithHTTPClient sets the HTTP client used to talk to the proxy, e.g. to change DefaultRequestTimeout.
+func (s *System) WithHTTPClient(client *http.Client) *System {
+	s.httpClient = client
+	return s
+}
+
+// WithRetryPolicy sets the retry policy of the invocations made by the system, replacing DefaultRetryPolicy.
+// It can be overridden per call with Options{"retry": policy}.
+func (s *System) WithRetryPolicy(policy RetryPolicy) *System {
+	s.retryPolicy = policy
+	return s
+}
+
+// PropagateMetadata sets the invocation metadata keys that handlers propagate
+// to the invocations and side effects they issue, replacing DefaultPropagatedMetadata.
+func (s *System) PropagateMetadata(keys ...string) *System {
+	s.propagatedMetadata = keys
+	return s
+}
+
 // RegisterActor registers a single actor in the system.
+// Registering another actor under an existing name is reported by Start.
 func (s *System) RegisterActor(actor *actors.Actor) *System {
+	if registered, ok := s.actors[actor.Name]; ok {
+		if registered != actor {
+			s.duplicateActors = append(s.duplicateActors, actor.Name)
+		}
+		return s
+	}
+
 	s.actors[actor.Name] = actor
 	return s
 }
@@ -73,12 +146,19 @@ func (s *System) BuildActor(config actors.ActorConfig) *actors.Actor {
 }
 
 // Start initializes the system by registering all configured actors with the sidecar.
+// The configuration is validated first and nothing is sent to the sidecar if it is invalid.
+// It fails without registering anything when the ActorHost port cannot be bound.
 func (s *System) Start() error {
-	if len(s.actors) == 0 {
-		return fmt.Errorf("no actors registered in the system")
+	if err := s.Validate(); err != nil {
+		return err
+	}
+	if s.metricsErr != nil {
+		return s.metricsErr
 	}
 
-	go s.startServer()
+	if err := s.startServer(); err != nil {
+		return err
+	}
 
 	// Converts actors into a Protobuf representation map
 	actorProtos := s.convertActorsToProtobuf()
@@ -103,22 +183,29 @@ func (s *System) Start() error {
 
 	data, err := proto.Marshal(registration)
 	if err != nil {
+		s.server.Close()
 		return fmt.Errorf("failed to serialize registration request: %w", err)
 	}
 
 	resp, err := s.postToSidecar(data)
 	if err != nil {
+		s.metrics.registrationAttempted(err)
+		s.server.Close()
 		return err
 	}
 
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("failed to register actors, status code: %d", resp.StatusCode)
+		err := fmt.Errorf("failed to register actors, status code: %d", resp.StatusCode)
+		s.metrics.registrationAttempted(err)
+		s.server.Close()
+		return err
 	}
+	s.metrics.registrationAttempted(nil)
 
 	go s.listenForTermination()
 
-	log.Println("Actors successfully registered and system started")
+	s.logger.Info("Actors successfully registered and system started", slog.Int("actors", len(actorProtos)))
 	///s.wg.Wait()
 	return nil
 }
@@ -131,13 +218,76 @@ func (s *System) Await() {
 	<-s.stopCh
 }
 
+// Errors reports the failures of the ActorHost that happen after Start returned,
+// e.g. invocations it could not process or the server stopping to serve.
+// Errors are dropped when nobody receives them. The channel is closed once the system has stopped.
+func (s *System) Errors() <-chan error {
+	return s.errCh
+}
+
 // client API
 func (s *System) Invoke(system string, actorName string, action string, request proto.Message, options Options) (proto.Message, error) {
-	log.Printf("Invoking actor: %s, action: %s", actorName, action)
+	return s.InvokeContext(context.Background(), system, actorName, action, request, options)
+}
+
+// InvokeContext is like Invoke, the request to the proxy is aborted when ctx is done.
+// The deadline of ctx is sent to the invoked actor in the DeadlineMetadataKey metadata.
+func (s *System) InvokeContext(ctx context.Context, system string, actorName string, action string, request proto.Message, options Options) (proto.Message, error) {
+	return s.invoke(ctx, nil, system, actorName, action, request, options)
+}
+
+// invokeFromActor invokes an actor on behalf of the actor identified by caller.
+func (s *System) invokeFromActor(ctx context.Context, caller actors.ActorId, system string, actorName string, action string, request proto.Message, options map[string]interface{}) (proto.Message, error) {
+	return s.invoke(ctx, &protocol.ActorId{
+		Name:   caller.Name,
+		System: caller.System,
+		Parent: caller.Parent,
+	}, system, actorName, action, request, options)
+}
+
+func (s *System) invoke(ctx context.Context, caller *protocol.ActorId, system string, actorName string, action string, request proto.Message, options Options) (proto.Message, error) {
 	parent, hasParent := options["parent"]
 	async, hasAsync := options["async"]
+	pooled, hasPooled := options["pooled"]
 	metadata, hasMetadata := options["metadata"]
 
+	policy := s.retryPolicy
+	if retry, hasRetry := options["retry"]; hasRetry {
+		retryPolicy, ok := retry.(RetryPolicy)
+		if !ok {
+			return nil, fmt.Errorf("retry must be a RetryPolicy")
+		}
+		policy = retryPolicy
+	}
+	idempotent := false
+	if value, hasIdempotent := options["idempotent"]; hasIdempotent {
+		idempotentBool, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("idempotent must be a bool")
+		}
+		idempotent = idempotentBool
+	}
+
+	// Unnamed instances are addressed by the field of the request annotated with (actor_id) on demand,
+	// actorName then names the actor they are spawned from
+	if value, hasRoute := options["route_by_actor_id"]; hasRoute {
+		route, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("route_by_actor_id must be a bool")
+		}
+		if route {
+			if hasParent {
+				return nil, fmt.Errorf("route_by_actor_id cannot be combined with parent")
+			}
+			id, ok := ActorIdFromMessage(request)
+			if !ok {
+				return nil, fmt.Errorf("cannot route by actor id, %T has no string or integer field annotated with (actor_id) = true or it is empty", request)
+			}
+			parent, hasParent = actorName, true
+			actorName = id
+		}
+	}
+
 	req := &protocol.InvocationRequest{}
 	req.System = &protocol.ActorSystem{Name: system}
 
@@ -163,12 +313,32 @@ func (s *System) Invoke(system string, actorName string, action string, request
 			return nil, fmt.Errorf("async must be a bool")
 		}
 	}
+	if hasPooled {
+		if pooledBool, ok := pooled.(bool); ok {
+			req.Pooled = pooledBool
+		} else {
+			return nil, fmt.Errorf("pooled must be a bool")
+		}
+	} else if local, ok := s.actors[actorName]; ok && system == s.name {
+		// Pooled actors of this system are dispatched to their pool by default
+		req.Pooled = local.Kind == actors.Pooled
+	}
 	if hasMetadata {
-		req.Metadata = metadata.(map[string]string)
+		if metadataMap, ok := metadata.(map[string]string); ok {
+			req.Metadata = metadataMap
+		} else {
+			return nil, fmt.Errorf("metadata must be a map[string]string")
+		}
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		req.Metadata = withDeadline(req.Metadata, deadline)
 	}
+	var invocationId string
+	req.Metadata, invocationId = withInvocationId(req.Metadata)
 
 	req.Actor = actor
 	req.ActionName = action
+	req.Caller = caller
 
 	if request != nil {
 		payload, err := anypb.New(request)
@@ -179,26 +349,46 @@ func (s *System) Invoke(system string, actorName string, action string, request
 		req.Payload = &protocol.InvocationRequest_Value{Value: payload}
 	}
 
+	logger := s.invocationLogger(actorName, action, invocationId)
+	logger.DebugContext(ctx, "Invoking actor", slog.Any("payload", request))
+
+	ctx, span := s.startClientSpan(ctx, req, invocationId)
+	defer span.End()
+
+	start := time.Now()
+	message, err := s.sendInvocation(ctx, req, policy, idempotent)
+	actorLabel, actionLabel := metricsLabels(actor.Id, action, err)
+	s.metrics.observeInvocation(actorLabel, actionLabel, len(req.GetValue().GetValue()), time.Since(start), err)
+	if err != nil {
+		recordError(span, err)
+		logger.WarnContext(ctx, "Actor invocation failed", slog.Duration(durationKey, time.Since(start)), slog.Any(errorKey, err))
+		return nil, err
+	}
+
+	logger.DebugContext(ctx, "Actor invoked", slog.Duration(durationKey, time.Since(start)), slog.Any("response", message))
+	return message, nil
+}
+
+// sendInvocation sends the invocation request to the proxy and decodes the response of the actor.
+func (s *System) sendInvocation(ctx context.Context, req *protocol.InvocationRequest, policy RetryPolicy, idempotent bool) (proto.Message, error) {
 	r, err := proto.Marshal(req)
 	if err != nil {
-		log.Fatalln("Failed to encode Actor InvocationRequest:", err)
+		return nil, fmt.Errorf("failed to encode Actor InvocationRequest: %w", err)
 	}
 
 	// call proxy to invoke actor
-	responseBytes, err := s.invokeActor(actorName, r)
+	responseBytes, err := s.invokeActor(ctx, req.Actor.Id.Name, r, policy, idempotent)
 	if err != nil {
 		return nil, err
 	}
 
 	resp := &protocol.InvocationResponse{}
 	if err := proto.Unmarshal(responseBytes, resp); err != nil {
-		log.Fatalln("Failed to parse Actor InvocationResponse:", err)
+		return nil, fmt.Errorf("failed to parse Actor InvocationResponse: %w", err)
 	}
 
-	log.Printf("Actor invocation response: %v", resp)
-
 	if resp.Status.GetStatus() != protocol.Status_OK {
-		return nil, fmt.Errorf("actor invocation failed: %s", resp.Status)
+		return nil, statusError(resp.Status)
 	}
 
 	var message proto.Message
@@ -206,9 +396,11 @@ func (s *System) Invoke(system string, actorName string, action string, request
 	switch p := resp.GetPayload().(type) {
 	case *protocol.InvocationResponse_Value:
 		iany := p.Value
-		log.Printf("Received response payload: %v", iany)
+		// Errors returned by the handler are reported as a response payload
+		if actorErr, ok := decodeError(iany); ok {
+			return nil, newRemoteError(actorErr)
+		}
 		msg, err := unmarshalAny(iany)
-		log.Printf("Unmarshalled response payload: %v", msg)
 		if err != nil {
 			return nil, fmt.Errorf("error unmarshalling response value: %v", err)
 		}
@@ -237,14 +429,22 @@ func (s *System) listenForTermination() {
 
 	// Block until a termination signal is received
 	sig := <-signalChan
-	log.Printf("Received %s, shutting down gracefully...", sig)
+	s.logger.Info("Shutting down gracefully", slog.String("signal", sig.String()))
 
-	// Tenta fechar o servidor HTTP
-	if err := s.server.Close(); err != nil {
-		log.Printf("Error closing the server: %v", err)
+	// Closes the ActorHost server, if it was started
+	if s.server != nil {
+		if err := s.server.Close(); err != nil {
+			s.reportError(fmt.Errorf("failed to close the ActorHost server: %w", err))
+		}
 	}
 
 	close(s.stopCh)
+
+	// Handlers still running after the server closed can no longer report errors
+	s.errMu.Lock()
+	s.errClosed = true
+	close(s.errCh)
+	s.errMu.Unlock()
 }
 
 // postToSidecar sends the serialized data to the Spawn sidecar API.
@@ -259,8 +459,7 @@ func (s *System) postToSidecar(data []byte) (*http.Response, error) {
 	req.Header.Set("Accept", "application/octet-stream")
 	req.Header.Set("Content-Type", "application/octet-stream")
 
-	client := &http.Client{}
-	return client.Do(req)
+	return s.httpClient.Do(req)
 }
 
 // convertActorsToProtobuf converts the registered actors into a map with actor names as keys and their Protobuf representation as values.
@@ -276,6 +475,15 @@ func (s *System) convertActorsToProtobuf() map[string]*protocol.Actor {
 			})
 		}
 
+		// Converting timer actions
+		timerActions := make([]*protocol.FixedTimerAction, 0, len(actor.TimerActions))
+		for actionName, timer := range actor.TimerActions {
+			timerActions = append(timerActions, &protocol.FixedTimerAction{
+				Seconds: timerSeconds(timer.Every),
+				Action:  &protocol.Action{Name: actionName},
+			})
+		}
+
 		// Creating snapshot strategy
 		var snapshotStrategy *protocol.ActorSnapshotStrategy
 		if actor.SnapshotTimeout > 0 {
@@ -300,6 +508,15 @@ func (s *System) convertActorsToProtobuf() map[string]*protocol.Actor {
 			}
 		}
 
+		// Converting channel subscriptions
+		channels := make([]*protocol.Channel, 0, len(actor.Channels))
+		for _, channel := range actor.Channels {
+			channels = append(channels, &protocol.Channel{
+				Topic:  channel.Topic,
+				Action: channelAction(channel),
+			})
+		}
+
 		// Configuring ActorSettings
 		settings := &protocol.ActorSettings{
 			Kind:                 mapKindFromGoToProto(actor.Kind),
@@ -308,6 +525,10 @@ func (s *System) convertActorsToProtobuf() map[string]*protocol.Actor {
 			DeactivationStrategy: deactivationStrategy,
 		}
 
+		if actor.ProjectionSettings != nil {
+			settings.ProjectionSettings = convertProjectionSettingsToProtobuf(actor.ProjectionSettings)
+		}
+
 		// Configuring pool size if the actor kind is pooled
 		if actor.Kind == actors.Pooled {
 			settings.MinPoolSize = actor.MinPoolSize
@@ -321,16 +542,60 @@ func (s *System) convertActorsToProtobuf() map[string]*protocol.Actor {
 				System: s.name,
 			},
 			State:        &protocol.ActorState{},
-			Metadata:     &protocol.Metadata{},
+			Metadata:     &protocol.Metadata{ChannelGroup: channels},
 			Settings:     settings,
 			Actions:      actions,
-			TimerActions: nil, // TODO: Implement timer actions
+			TimerActions: timerActions,
 		}
 	}
 
 	return actorMap
 }
 
+// timerSeconds rounds a timer interval up to the whole seconds expected by the proxy.
+func timerSeconds(every time.Duration) int32 {
+	seconds := int32((every + time.Second - 1) / time.Second)
+	if seconds < 1 {
+		return 1
+	}
+	return seconds
+}
+
+func convertProjectionSettingsToProtobuf(projection *actors.ProjectionSettings) *protocol.ProjectionSettings {
+	subjects := make([]*protocol.ProjectionSubject, 0, len(projection.Subjects))
+	for _, subject := range projection.Subjects {
+		protoSubject := &protocol.ProjectionSubject{
+			Actor:  subject.Actor,
+			Action: subject.Action,
+		}
+		if !subject.StartTime.IsZero() {
+			protoSubject.StartTime = timestamppb.New(subject.StartTime)
+		}
+		subjects = append(subjects, protoSubject)
+	}
+
+	var retentionStrategy *protocol.EventsRetentionStrategy
+	switch {
+	case projection.EventsRetention == actors.InfiniteRetention:
+		retentionStrategy = &protocol.EventsRetentionStrategy{
+			Strategy: &protocol.EventsRetentionStrategy_Infinite{Infinite: true},
+		}
+	case projection.EventsRetention > 0:
+		retentionStrategy = &protocol.EventsRetentionStrategy{
+			Strategy: &protocol.EventsRetentionStrategy_TimeInMs{
+				TimeInMs: &protocol.EventsRetentionTime{Time: projection.EventsRetention.Milliseconds()},
+			},
+		}
+	}
+
+	return &protocol.ProjectionSettings{
+		Subjects:                subjects,
+		Sourceable:              projection.Sourceable,
+		EventsRetentionStrategy: retentionStrategy,
+		StrictEventsOrdering:    projection.StrictEventsOrdering,
+	}
+}
+
 func mapKindFromGoToProto(kind actors.Kind) protocol.Kind {
 	switch kind {
 	case actors.Named:
@@ -339,6 +604,8 @@ func mapKindFromGoToProto(kind actors.Kind) protocol.Kind {
 		return protocol.Kind_UNNAMED
 	case actors.Pooled:
 		return protocol.Kind_POOLED
+	case actors.Proxy:
+		return protocol.Kind_PROXY
 	case actors.Task:
 		return protocol.Kind_TASK
 	case actors.Projection:
@@ -348,42 +615,105 @@ func mapKindFromGoToProto(kind actors.Kind) protocol.Kind {
 	}
 }
 
-func (s *System) startServer() {
-	http.HandleFunc("/api/v1/actors/actions", s.handleActorInvocation)
+// startServer binds the ActorHost port and serves the actor invocations in the background.
+func (s *System) startServer() error {
 	addr := fmt.Sprintf(":%d", s.exposePort)
-	log.Printf("ActorHost server started on port %d\n", s.exposePort)
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to bind ActorHost port %d: %w", s.exposePort, err)
+	}
 
-	s.server = &http.Server{Addr: addr}
+	s.server = &http.Server{
+		Addr:     addr,
+		Handler:  s.newServeMux(),
+		ErrorLog: slog.NewLogLogger(s.logger.Handler(), slog.LevelError),
+	}
+	s.logger.Info("ActorHost server started", slog.Int("port", s.exposePort))
 
 	// Adds the goroutine to the WaitGroup to wait for its completion
 	s.wg.Add(1)
-	defer s.wg.Done()
+	go func() {
+		defer s.wg.Done()
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			s.reportError(fmt.Errorf("ActorHost server failed: %w", err))
+		}
+	}()
+
+	return nil
+}
 
-	if err := s.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		log.Fatalf("ActorHost server failed: %v", err)
+// newServeMux returns the routes of the ActorHost.
+func (s *System) newServeMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/actors/actions", s.handleActorInvocation)
+	if s.metricsHandler != nil {
+		mux.Handle(MetricsPath, s.metricsHandler)
+	}
+	return mux
+}
+
+// reportError hands err over to Errors() without blocking.
+func (s *System) reportError(err error) {
+	s.logger.Error("Actor System failure", slog.Any(errorKey, err))
+
+	s.errMu.Lock()
+	defer s.errMu.Unlock()
+	if s.errClosed {
+		return
+	}
+	select {
+	case s.errCh <- err:
+	default:
 	}
 }
 
 func (s *System) handleActorInvocation(w http.ResponseWriter, r *http.Request) {
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("failed to read request body: %v", err), http.StatusInternalServerError)
+		err = fmt.Errorf("failed to read request body: %w", err)
+		s.reportError(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	var actorInvocation protocol.ActorInvocation
 	if err := proto.Unmarshal(body, &actorInvocation); err != nil {
-		http.Error(w, fmt.Sprintf("failed to unmarshal protobuf: %v", err), http.StatusBadRequest)
+		err = fmt.Errorf("failed to unmarshal protobuf: %w", err)
+		s.reportError(err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if actorInvocation.GetActor().GetName() == "" {
+		err := fmt.Errorf("actor invocation of action %q does not name an actor", actorInvocation.GetActionName())
+		s.reportError(err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Process the invocation
-	log.Printf("Received actor invocation: %v", &actorInvocation)
-	resp := s.processActorInvocation(&actorInvocation)
+	ctx := r.Context()
+	invocationId := actorInvocation.GetCurrentContext().GetMetadata()[InvocationIdMetadataKey]
+	logger := s.invocationLogger(actorInvocation.GetActor().GetName(), actorInvocation.GetActionName(), invocationId)
+	logger.DebugContext(ctx, "Received actor invocation")
+
+	// Process the invocation, the request context is cancelled when the proxy disconnects
+	start := time.Now()
+	resp, err := s.processActorInvocation(ctx, logger, &actorInvocation)
+	_, handlerFailed := decodeError(resp.GetValue())
+	actorLabel, actionLabel := s.hostMetricsLabels(&actorInvocation)
+	s.metrics.observeHostInvocation(actorLabel, actionLabel, time.Since(start), err != nil || handlerFailed)
+	if err != nil {
+		logger.WarnContext(ctx, "Failed to process actor invocation", slog.Duration(durationKey, time.Since(start)), slog.Any(errorKey, err))
+		s.reportError(fmt.Errorf("failed to process actor invocation: %w", err))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	logger.DebugContext(ctx, "Actor invocation processed", slog.Duration(durationKey, time.Since(start)))
 
 	payloadBytes, err := proto.Marshal(resp)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("failed to marshal protobuf response: %v", err), http.StatusInternalServerError)
+		err = fmt.Errorf("failed to marshal protobuf response: %w", err)
+		s.reportError(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
@@ -392,13 +722,32 @@ func (s *System) handleActorInvocation(w http.ResponseWriter, r *http.Request) {
 	w.Write(payloadBytes)
 }
 
-func (s *System) processActorInvocation(actorInvocation *protocol.ActorInvocation) *protocol.ActorInvocationResponse {
-	log.Printf("Processing actor invocation: %v", actorInvocation)
+func (s *System) processActorInvocation(ctx context.Context, logger *slog.Logger, actorInvocation *protocol.ActorInvocation) (_ *protocol.ActorInvocationResponse, err error) {
+	// The span of the action is handed over to the handler through its context
+	ctx, span := s.startServerSpan(ctx, actorInvocation)
+	defer func() {
+		if err != nil {
+			recordError(span, err)
+		}
+		span.End()
+	}()
 
-	actorName := actorInvocation.Actor.Name
-	actionName := actorInvocation.ActionName
-	requestContext := actorInvocation.CurrentContext
-	actualStateAny := requestContext.State
+	actorName := actorInvocation.GetActor().GetName()
+	actionName := actorInvocation.GetActionName()
+	requestContext := actorInvocation.GetCurrentContext()
+	actualStateAny := requestContext.GetState()
+
+	actor, ok := s.lookupActor(actorInvocation.GetActor())
+	if !ok {
+		return s.errorResponse(actorInvocation, actors.Errorf(actors.CodeActorNotFound, "actor %s is not registered in system %s", actorName, s.name))
+	}
+
+	// Timer actions are dispatched the same way, their ticks carry only the state
+	actionHandler, ok := actor.Handler(actionName)
+	if !ok {
+		return s.errorResponse(actorInvocation, actors.Errorf(actors.CodeActionNotFound, "action %s not found for actor %s", actionName, actorName))
+	}
+	s.metrics.observeHostPayload(actor.Name, actionName, len(actorInvocation.GetValue().GetValue()))
 
 	var req proto.Message
 	switch payload := actorInvocation.Payload.(type) {
@@ -406,88 +755,278 @@ func (s *System) processActorInvocation(actorInvocation *protocol.ActorInvocatio
 		// Deserialize the payload
 		request, err := unmarshalAny(payload.Value)
 		if err != nil {
-			log.Printf("Failed to unmarshal payload: %v", err)
-			return &protocol.ActorInvocationResponse{ActorName: actorName, ActorSystem: s.name}
+			return s.errorResponse(actorInvocation, actors.Errorf(actors.CodeInvalidPayload, "failed to unmarshal payload for actor %s: %v", actorName, err))
 		}
 		req = request
-	case *protocol.ActorInvocation_Noop:
-		log.Printf("No operation payload received for actor: %s", actorName)
 	}
+	logger.DebugContext(ctx, "Processing actor invocation", slog.Any("payload", req))
 
-	actor, ok := s.actors[actorName]
-	if !ok {
-		log.Printf("Actor not found: %s", actorName)
-		return &protocol.ActorInvocationResponse{ActorName: actorName, ActorSystem: s.name}
+	// Unmarshal the actor's current state, new actors start from their initial state
+	stateValue, err := decodeState(actor, actualStateAny)
+	if err != nil {
+		return s.errorResponse(actorInvocation, actors.Errorf(actors.CodeInvalidState, "invalid state for actor %s: %v", actorName, err))
 	}
 
-	actionHandler, ok := actor.Actions[actionName]
-	if !ok {
-		log.Printf("Action not found: %s for actor %s", actionName, actorName)
-		return &protocol.ActorInvocationResponse{ActorName: actorName, ActorSystem: s.name}
+	// Invoke the action handler
+	propagatedMetadata := s.selectPropagatedMetadata(requestContext.GetMetadata())
+	self := actorIdFromProtobuf(requestContext.GetSelf())
+	if self == nil {
+		self = actorIdFromProtobuf(actorInvocation.GetActor())
 	}
-
-	fmt.Printf("Marshalled Any: %v\n", actualStateAny)
-	// Unmarshal the actor's current state
-	var stateValue proto.Message
-	if actualStateAny == nil {
-		log.Printf("State not found for actor %s", actorName)
-	} else {
-		actualStateValue, err := unmarshalAny(actualStateAny)
-		if err != nil {
-			log.Printf("Failed to unmarshal state for actor %s: %v", actorName, err)
-			return &protocol.ActorInvocationResponse{ActorName: actorName, ActorSystem: s.name}
-		}
-
-		stateValue = actualStateValue
+	contextOptions := []actors.ActorContextOption{
+		actors.WithSelf(*self),
+		actors.WithTags(requestContext.GetTags()),
+		actors.WithMetadata(requestContext.GetMetadata()),
+		actors.WithPropagatedMetadata(propagatedMetadata),
+		actors.WithInvoker(s.invokeFromActor),
+	}
+	caller := actorIdFromProtobuf(actorInvocation.GetCaller())
+	if caller == nil {
+		caller = actorIdFromProtobuf(requestContext.GetCaller())
+	}
+	if caller != nil {
+		contextOptions = append(contextOptions, actors.WithCaller(*caller))
 	}
 
-	// Invoke the action handler
-	value, err := actionHandler(&actors.ActorContext{CurrentState: stateValue}, req)
+	// Honor the deadline of the caller, if any
+	deadline, err := deadlineFromMetadata(requestContext.GetMetadata())
 	if err != nil {
-		log.Printf("Error invoking action: %s for actor %s, error: %v", actionName, actorName, err)
-		return &protocol.ActorInvocationResponse{ActorName: actorName, ActorSystem: s.name}
+		logger.WarnContext(ctx, "Ignoring invalid deadline", slog.Any(errorKey, err))
+	} else if !deadline.IsZero() {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
 	}
 
-	log.Printf("Action [%s] response: %s for actor %s", actionName, value, actorName)
+	actorContext := actors.NewActorContext(stateValue, append(contextOptions, actors.WithContext(ctx))...)
+
+	value, err := s.callHandler(actor, actionName, actionHandler, actorContext, req)
+	if err != nil {
+		logger.InfoContext(ctx, "Action handler failed", slog.Any(errorKey, err))
+		recordError(span, err)
+		return s.errorResponse(actorInvocation, handlerError(err))
+	}
 
-	// Marshal the returned value into an Any type
-	//payloadAny, err := anypb.New(value)
-	// if err != nil {
-	// 	log.Printf("Failed to marshal response payload: %v", err)
-	// 	return &protocol.ActorInvocationResponse{}
-	// }
+	logger.DebugContext(ctx, "Action handled", slog.Any("state", value.State), slog.Any("response", value.Response))
 
 	// Create the updated context
 	var updatedState *anypb.Any = actualStateAny
 	if value.State != nil {
+		if err := checkStateType(actor, value.State); err != nil {
+			return s.errorResponse(actorInvocation, actors.Errorf(actors.CodeInvalidState, "action %s of actor %s returned an invalid state: %v", actionName, actorName, err))
+		}
+
 		us, err := anypb.New(value.State)
 		if err != nil {
-			log.Printf("Failed to marshal updated state: %v", err)
-			return &protocol.ActorInvocationResponse{ActorName: actorName, ActorSystem: s.name}
+			return nil, fmt.Errorf("failed to marshal updated state for actor %s: %w", actorName, err)
 		}
 
 		updatedState = us
 	}
+	s.metrics.observeState(actor.Name, len(updatedState.GetValue()))
+
+	// Tags are kept unless the handler replaced them
+	updatedTags := requestContext.GetTags()
+	if value.Tags != nil {
+		updatedTags = value.Tags
+	}
 
 	updatedContext := &protocol.Context{
 		State: updatedState,
+		Tags:  updatedTags,
 	}
 
-	log.Printf("Value Response: %v", value.Response)
-	responPayload, err := anypb.New(value.Response)
+	// Side effects continue the trace of the handler
+	workflow, err := s.convertWorkflowToProtobuf(value.Workflow, withTraceContext(ctx, propagatedMetadata))
 	if err != nil {
-		log.Printf("Failed to marshal response payload: %v", err)
-		return &protocol.ActorInvocationResponse{ActorName: actorName, ActorSystem: s.name}
+		return s.errorResponse(actorInvocation, actors.Errorf(actors.CodeHandlerFailed, "action %s of actor %s returned an invalid workflow: %v", actionName, actorName, err))
 	}
 
-	return &protocol.ActorInvocationResponse{
+	response := &protocol.ActorInvocationResponse{
 		ActorName:      actorName,
 		ActorSystem:    s.name,
 		UpdatedContext: updatedContext,
-		Payload:        &protocol.ActorInvocationResponse_Value{Value: responPayload},
-		Workflow:       nil,   // Populate if needed
+		Workflow:       workflow,
 		Checkpoint:     false, // Example: enable checkpointing
 	}
+
+	// Actions that pipe or forward may have no response of their own
+	if value.Response == nil {
+		response.Payload = &protocol.ActorInvocationResponse_Noop{Noop: &protocol.Noop{}}
+		return response, nil
+	}
+
+	responPayload, err := anypb.New(value.Response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal response payload for actor %s: %w", actorName, err)
+	}
+	response.Payload = &protocol.ActorInvocationResponse_Value{Value: responPayload}
+
+	return response, nil
+}
+
+// lookupActor returns the registered actor handling id.
+// Unnamed instances are handled by the actor registered as their parent.
+func (s *System) lookupActor(id *protocol.ActorId) (*actors.Actor, bool) {
+	actor, ok := s.actors[id.GetName()]
+	if !ok && id.GetParent() != "" {
+		actor, ok = s.actors[id.GetParent()]
+	}
+	return actor, ok
+}
+
+// callHandler runs the action handler, counting it as in flight until it returns or panics.
+// A panic is reported on Errors() and reaches the caller as a CodeHandlerFailed error.
+func (s *System) callHandler(actor *actors.Actor, action string, handler actors.ActionHandler, ctx *actors.ActorContext, payload proto.Message) (value actors.Value, err error) {
+	defer s.metrics.handlerStarted(actor.Name)()
+	defer func() {
+		if r := recover(); r != nil {
+			s.reportError(fmt.Errorf("action %s of actor %s panicked: %v\n%s", action, actor.Name, r, debug.Stack()))
+			value, err = actors.Value{}, actors.Errorf(actors.CodeHandlerFailed, "action %s of actor %s panicked: %v", action, actor.Name, r)
+		}
+	}()
+	return handler(ctx, payload)
+}
+
+// errorResponse reports err to the caller, keeping the state and tags of the actor unchanged.
+func (s *System) errorResponse(actorInvocation *protocol.ActorInvocation, err *actors.Error) (*protocol.ActorInvocationResponse, error) {
+	actorLabel, actionLabel := s.hostMetricsLabels(actorInvocation)
+	s.metrics.handlerError(actorLabel, actionLabel, err.Code)
+
+	payload, e := encodeError(err)
+	if e != nil {
+		return nil, fmt.Errorf("failed to encode error for actor %s: %w", actorInvocation.GetActor().GetName(), e)
+	}
+
+	requestContext := actorInvocation.GetCurrentContext()
+	return &protocol.ActorInvocationResponse{
+		ActorName:   actorInvocation.GetActor().GetName(),
+		ActorSystem: s.name,
+		UpdatedContext: &protocol.Context{
+			State: requestContext.GetState(),
+			Tags:  requestContext.GetTags(),
+		},
+		Payload: &protocol.ActorInvocationResponse_Value{Value: payload},
+	}, nil
+}
+
+// actorIdFromProtobuf converts a Protobuf ActorId, returning nil when it does not identify an actor.
+func actorIdFromProtobuf(id *protocol.ActorId) *actors.ActorId {
+	if id.GetName() == "" {
+		return nil
+	}
+	return &actors.ActorId{
+		Name:   id.GetName(),
+		System: id.GetSystem(),
+		Parent: id.GetParent(),
+	}
+}
+
+// registeredActorName returns the name of the registered actor handling id. Metrics and spans
+// report Unnamed instances under their parent to keep the number of series and span names bounded.
+func registeredActorName(id *protocol.ActorId) string {
+	if id.GetParent() != "" {
+		return id.GetParent()
+	}
+	return id.GetName()
+}
+
+// selectPropagatedMetadata returns the entries of the invocation metadata that must be propagated onward.
+func (s *System) selectPropagatedMetadata(metadata map[string]string) map[string]string {
+	propagated := make(map[string]string, len(s.propagatedMetadata))
+	for _, key := range s.propagatedMetadata {
+		if value, ok := metadata[key]; ok {
+			propagated[key] = value
+		}
+	}
+	return propagated
+}
+
+// withDeadline returns a copy of the metadata carrying the deadline, keeping an earlier one already present.
+func withDeadline(metadata map[string]string, deadline time.Time) map[string]string {
+	if current, err := deadlineFromMetadata(metadata); err == nil && !current.IsZero() && current.Before(deadline) {
+		return metadata
+	}
+
+	withDeadline := make(map[string]string, len(metadata)+1)
+	for key, value := range metadata {
+		withDeadline[key] = value
+	}
+	withDeadline[DeadlineMetadataKey] = deadline.UTC().Format(time.RFC3339Nano)
+	return withDeadline
+}
+
+// deadlineFromMetadata parses the deadline sent along with an invocation, the zero time when there is none.
+func deadlineFromMetadata(metadata map[string]string) (time.Time, error) {
+	value, ok := metadata[DeadlineMetadataKey]
+	if !ok {
+		return time.Time{}, nil
+	}
+
+	deadline, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s metadata %q: %w", DeadlineMetadataKey, value, err)
+	}
+	return deadline, nil
+}
+
+// mergeMetadata returns the propagated metadata overridden by the explicitly set entries.
+func mergeMetadata(propagated map[string]string, explicit map[string]string) map[string]string {
+	if len(propagated) == 0 {
+		return explicit
+	}
+
+	merged := make(map[string]string, len(propagated)+len(explicit))
+	for key, value := range propagated {
+		merged[key] = value
+	}
+	for key, value := range explicit {
+		merged[key] = value
+	}
+	return merged
+}
+
+// decodeState decodes the current state of an actor into its StateType.
+// When the proxy has no state yet, the actor's initial state is returned instead.
+func decodeState(actor *actors.Actor, stateAny *anypb.Any) (proto.Message, error) {
+	if stateAny == nil {
+		state := actor.NewState()
+		if state != nil {
+			if err := checkStateType(actor, state); err != nil {
+				return nil, fmt.Errorf("initial %w", err)
+			}
+		}
+		return state, nil
+	}
+
+	if actor.StateType == nil {
+		return unmarshalAny(stateAny)
+	}
+
+	if !stateAny.MessageIs(actor.StateType) {
+		return nil, fmt.Errorf("state type %s does not match %s", stateAny.GetTypeUrl(), actor.StateType.ProtoReflect().Descriptor().FullName())
+	}
+
+	state := actor.StateType.ProtoReflect().New().Interface()
+	if err := stateAny.UnmarshalTo(state); err != nil {
+		return nil, fmt.Errorf("unmarshalling failed: %w", err)
+	}
+
+	return state, nil
+}
+
+// checkStateType verifies that a state returned by a handler is of the actor's StateType.
+func checkStateType(actor *actors.Actor, state proto.Message) error {
+	if actor.StateType == nil {
+		return nil
+	}
+
+	expected := actor.StateType.ProtoReflect().Descriptor().FullName()
+	if actual := state.ProtoReflect().Descriptor().FullName(); actual != expected {
+		return fmt.Errorf("state type %s does not match %s", actual, expected)
+	}
+
+	return nil
 }
 
 func unmarshalAny(iany *anypb.Any) (proto.Message, error) {
@@ -516,39 +1055,55 @@ func unmarshalAny(iany *anypb.Any) (proto.Message, error) {
 	return message, nil
 }
 
-func (s *System) invokeActor(actorName string, requestBytes []byte) ([]byte, error) {
-	// Monta a URL de invocação do ator remoto
+func (s *System) invokeActor(ctx context.Context, actorName string, requestBytes []byte, policy RetryPolicy, idempotent bool) ([]byte, error) {
+	// Builds the invocation URL of the remote actor
 	url := fmt.Sprintf("%s:%d/api/v1/system/%s/actors/%s/invoke", s.url, s.proxyPort, s.name, actorName)
 
-	// Configura os cabeçalhos HTTP
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(requestBytes))
+	maxAttempts := policy.maxAttempts(idempotent)
+	for attempt := 1; ; attempt++ {
+		respBody, statusCode, err := s.postInvocation(ctx, url, requestBytes)
+		if err == nil {
+			return respBody, nil
+		}
+
+		if attempt >= maxAttempts || !policy.shouldRetry(ctx, statusCode, err) {
+			return nil, &InvocationError{Actor: actorName, StatusCode: statusCode, Attempts: attempt, Err: err}
+		}
+
+		s.logger.WarnContext(ctx, "Actor invocation attempt failed, retrying",
+			slog.String(actorKey, actorName), slog.Int("attempt", attempt), slog.Int("max_attempts", maxAttempts), slog.Any(errorKey, err))
+		if err := sleepContext(ctx, policy.backoff(attempt)); err != nil {
+			return nil, &InvocationError{Actor: actorName, StatusCode: statusCode, Attempts: attempt, Err: err}
+		}
+	}
+}
+
+// postInvocation makes a single invocation attempt, returning the HTTP status code when a response was received.
+func (s *System) postInvocation(ctx context.Context, url string, requestBytes []byte) ([]byte, int, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(requestBytes))
 	if err != nil {
-		return nil, fmt.Errorf("erro ao criar requisição: %v", err)
+		return nil, 0, fmt.Errorf("failed to create HTTP request: %w", err)
 	}
 
 	req.Header.Set("User-Agent", "user-function-client/0.1.0")
 	req.Header.Set("Accept", "application/octet-stream")
 	req.Header.Set("Content-Type", "application/octet-stream")
 
-	// Envia a requisição HTTP POST
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := s.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao enviar requisição: %v", err)
+		return nil, 0, fmt.Errorf("failed to send HTTP request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	// Verifica a resposta
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("falha na invocação do ator. Status: %d, Erro: %s", resp.StatusCode, string(body))
+		return nil, resp.StatusCode, fmt.Errorf("actor invocation failed, status code: %d, error: %s", resp.StatusCode, string(body))
 	}
 
-	// Lê o conteúdo da resposta
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao ler resposta: %v", err)
+		return nil, resp.StatusCode, fmt.Errorf("failed to read HTTP response: %w", err)
 	}
 
-	return respBody, nil
+	return respBody, resp.StatusCode, nil
 }
@@ -0,0 +1,338 @@
+package system
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/eigr/spawn-go-sdk/spawn/actors"
+	protocol "github.com/eigr/spawn-go-sdk/spawn/eigr/functions/protocol/actors"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// newTestProxySystem returns a system whose proxy is served by handler.
+func newTestProxySystem(t *testing.T, handler http.HandlerFunc) *System {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, err := strconv.Atoi(serverURL.Port())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewSystem("test-system").UseProxyPort(port)
+}
+
+// postActorInvocation sends the invocation to the ActorHost of s and returns the recorded response.
+func postActorInvocation(t *testing.T, s *System, invocation *protocol.ActorInvocation) *httptest.ResponseRecorder {
+	t.Helper()
+
+	body, err := proto.Marshal(invocation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := httptest.NewRecorder()
+	s.handleActorInvocation(recorder, httptest.NewRequest(http.MethodPost, "/api/v1/actors/actions", bytes.NewReader(body)))
+	return recorder
+}
+
+// decodeActorInvocationResponse decodes the response of a successful invocation of the ActorHost.
+func decodeActorInvocationResponse(t *testing.T, recorder *httptest.ResponseRecorder) *protocol.ActorInvocationResponse {
+	t.Helper()
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", recorder.Code, http.StatusOK, recorder.Body)
+	}
+	resp := &protocol.ActorInvocationResponse{}
+	if err := proto.Unmarshal(recorder.Body.Bytes(), resp); err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestHandleActorInvocationWithoutActor(t *testing.T) {
+	s := NewSystem("test-system")
+
+	recorder := postActorInvocation(t, s, &protocol.ActorInvocation{ActionName: "act"})
+
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", recorder.Code, http.StatusBadRequest)
+	}
+	select {
+	case <-s.Errors():
+	default:
+		t.Error("no error reported on Errors()")
+	}
+}
+
+func TestHandleActorInvocationWithoutContext(t *testing.T) {
+	actor := actors.ActorOf(actors.ActorConfig{Name: "A", Kind: actors.Named})
+	actor.AddAction("act", func(ctx *actors.ActorContext, payload proto.Message) (actors.Value, error) {
+		return actors.Of(wrapperspb.String("done")).Materialize(), nil
+	})
+	s := NewSystem("test-system")
+	s.RegisterActor(actor)
+
+	resp := decodeActorInvocationResponse(t, postActorInvocation(t, s, &protocol.ActorInvocation{
+		Actor:      &protocol.ActorId{Name: "A", System: "test-system"},
+		ActionName: "act",
+	}))
+
+	response := &wrapperspb.StringValue{}
+	if err := resp.GetValue().UnmarshalTo(response); err != nil || response.GetValue() != "done" {
+		t.Errorf("response = %v (%v), want %q", response, err, "done")
+	}
+}
+
+func TestHandleActorInvocationPanickingHandler(t *testing.T) {
+	actor := actors.ActorOf(actors.ActorConfig{Name: "A", Kind: actors.Named})
+	actor.AddAction("act", func(ctx *actors.ActorContext, payload proto.Message) (actors.Value, error) {
+		panic("boom")
+	})
+	s := NewSystem("test-system")
+	s.RegisterActor(actor)
+
+	resp := decodeActorInvocationResponse(t, postActorInvocation(t, s, &protocol.ActorInvocation{
+		Actor:          &protocol.ActorId{Name: "A", System: "test-system"},
+		ActionName:     "act",
+		CurrentContext: &protocol.Context{},
+	}))
+
+	actorErr, ok := decodeError(resp.GetValue())
+	if !ok {
+		t.Fatalf("response payload = %v, want an error", resp.GetValue())
+	}
+	if actorErr.Code != actors.CodeHandlerFailed || !strings.Contains(actorErr.Message, "boom") {
+		t.Errorf("error = %v, want %s mentioning the panic", actorErr, actors.CodeHandlerFailed)
+	}
+	select {
+	case <-s.Errors():
+	default:
+		t.Error("no error reported on Errors()")
+	}
+}
+
+func TestHandleActorInvocationInvalidWorkflow(t *testing.T) {
+	tests := []struct {
+		name     string
+		workflow *actors.Workflow
+	}{
+		{
+			name: "pipe and forward",
+			workflow: &actors.Workflow{
+				Pipe:    &actors.Pipe{Actor: "B", Action: "act"},
+				Forward: &actors.Forward{Actor: "C", Action: "act"},
+			},
+		},
+		{name: "pipe without action", workflow: &actors.Workflow{Pipe: &actors.Pipe{Actor: "B"}}},
+		{name: "forward without actor", workflow: &actors.Workflow{Forward: &actors.Forward{Action: "act"}}},
+		{name: "broadcast without channel group", workflow: &actors.Workflow{Broadcast: &actors.Broadcast{}}},
+		{name: "side effect without actor", workflow: &actors.Workflow{Effects: []actors.SideEffect{{Action: "act"}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actor := actors.ActorOf(actors.ActorConfig{Name: "A", Kind: actors.Named})
+			actor.AddAction("act", func(ctx *actors.ActorContext, payload proto.Message) (actors.Value, error) {
+				return actors.Value{Response: wrapperspb.String("done"), Workflow: tt.workflow}, nil
+			})
+			s := NewSystem("test-system")
+			s.RegisterActor(actor)
+
+			resp := decodeActorInvocationResponse(t, postActorInvocation(t, s, &protocol.ActorInvocation{
+				Actor:          &protocol.ActorId{Name: "A", System: "test-system"},
+				ActionName:     "act",
+				CurrentContext: &protocol.Context{},
+			}))
+
+			actorErr, ok := decodeError(resp.GetValue())
+			if !ok {
+				t.Fatalf("response payload = %v, want an error", resp.GetValue())
+			}
+			if actorErr.Code != actors.CodeHandlerFailed || !strings.Contains(actorErr.Message, "invalid workflow") {
+				t.Errorf("error = %v, want %s mentioning the invalid workflow", actorErr, actors.CodeHandlerFailed)
+			}
+			if resp.GetWorkflow() != nil {
+				t.Errorf("workflow = %v, want none", resp.GetWorkflow())
+			}
+		})
+	}
+}
+
+func TestActorContextInvoke(t *testing.T) {
+	var received protocol.InvocationRequest
+	s := newTestProxySystem(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := proto.Unmarshal(body, &received); err != nil {
+			t.Errorf("proxy received an invalid InvocationRequest: %v", err)
+		}
+		resp, _ := proto.Marshal(&protocol.InvocationResponse{
+			Status:  &protocol.RequestStatus{Status: protocol.Status_OK},
+			Payload: &protocol.InvocationResponse_Noop{Noop: &protocol.Noop{}},
+		})
+		w.Write(resp)
+	})
+
+	actor := actors.ActorOf(actors.ActorConfig{Name: "UserActor", Kind: actors.Unnamed})
+	actor.AddAction("act", func(ctx *actors.ActorContext, payload proto.Message) (actors.Value, error) {
+		_, err := ctx.Invoke("", "AuditActor", "Record", nil, map[string]interface{}{
+			"metadata": map[string]string{"tenant-id": "explicit", "request": "1"},
+		})
+		return actors.Value{}, err
+	})
+	s.RegisterActor(actor)
+
+	resp := decodeActorInvocationResponse(t, postActorInvocation(t, s, &protocol.ActorInvocation{
+		Actor:      &protocol.ActorId{Name: "user-42", System: "test-system", Parent: "UserActor"},
+		ActionName: "act",
+		CurrentContext: &protocol.Context{Metadata: map[string]string{
+			"tenant-id":      "propagated",
+			"correlation-id": "c-1",
+			"not-propagated": "x",
+		}},
+	}))
+	if actorErr, ok := decodeError(resp.GetValue()); ok {
+		t.Fatalf("action failed: %v", actorErr)
+	}
+
+	wantCaller := &protocol.ActorId{Name: "user-42", System: "test-system", Parent: "UserActor"}
+	if !proto.Equal(received.GetCaller(), wantCaller) {
+		t.Errorf("caller = %v, want %v", received.GetCaller(), wantCaller)
+	}
+	wantMetadata := map[string]string{"tenant-id": "explicit", "correlation-id": "c-1", "request": "1"}
+	for key, want := range wantMetadata {
+		if got := received.GetMetadata()[key]; got != want {
+			t.Errorf("metadata[%q] = %q, want %q", key, got, want)
+		}
+	}
+	if _, ok := received.GetMetadata()["not-propagated"]; ok {
+		t.Error("metadata not listed in the propagated keys was sent")
+	}
+}
+
+func TestWithDeadline(t *testing.T) {
+	deadline := time.Date(2026, 1, 2, 3, 4, 5, 6, time.UTC)
+	earlier := deadline.Add(-time.Second).Format(time.RFC3339Nano)
+	later := deadline.Add(time.Second).Format(time.RFC3339Nano)
+
+	tests := []struct {
+		name     string
+		metadata map[string]string
+		want     string
+	}{
+		{name: "no metadata", want: deadline.Format(time.RFC3339Nano)},
+		{name: "earlier deadline", metadata: map[string]string{DeadlineMetadataKey: earlier}, want: earlier},
+		{name: "later deadline", metadata: map[string]string{DeadlineMetadataKey: later}, want: deadline.Format(time.RFC3339Nano)},
+		{name: "invalid deadline", metadata: map[string]string{DeadlineMetadataKey: "tomorrow"}, want: deadline.Format(time.RFC3339Nano)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := tt.metadata[DeadlineMetadataKey]
+
+			got := withDeadline(tt.metadata, deadline.In(time.FixedZone("UTC+2", 2*60*60)))
+			if got[DeadlineMetadataKey] != tt.want {
+				t.Errorf("withDeadline()[%q] = %q, want %q", DeadlineMetadataKey, got[DeadlineMetadataKey], tt.want)
+			}
+			if tt.metadata[DeadlineMetadataKey] != original {
+				t.Errorf("withDeadline() modified the metadata to %v", tt.metadata)
+			}
+		})
+	}
+}
+
+func TestDeadlineFromMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata map[string]string
+		want     time.Time
+		wantErr  bool
+	}{
+		{name: "no deadline"},
+		{
+			name:     "deadline",
+			metadata: map[string]string{DeadlineMetadataKey: "2026-01-02T03:04:05.000000006Z"},
+			want:     time.Date(2026, 1, 2, 3, 4, 5, 6, time.UTC),
+		},
+		{name: "empty", metadata: map[string]string{DeadlineMetadataKey: ""}, wantErr: true},
+		{name: "unix time", metadata: map[string]string{DeadlineMetadataKey: "1767323045"}, wantErr: true},
+		{name: "without zone", metadata: map[string]string{DeadlineMetadataKey: "2026-01-02T03:04:05"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := deadlineFromMetadata(tt.metadata)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("deadlineFromMetadata() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("deadlineFromMetadata() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeadlineRoundTrip(t *testing.T) {
+	var received protocol.InvocationRequest
+	s := newTestProxySystem(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := proto.Unmarshal(body, &received); err != nil {
+			t.Errorf("proxy received an invalid InvocationRequest: %v", err)
+		}
+		resp, _ := proto.Marshal(&protocol.InvocationResponse{
+			Status:  &protocol.RequestStatus{Status: protocol.Status_OK},
+			Payload: &protocol.InvocationResponse_Noop{Noop: &protocol.Noop{}},
+		})
+		w.Write(resp)
+	})
+
+	var handlerDeadline time.Time
+	var hasDeadline bool
+	actor := actors.ActorOf(actors.ActorConfig{Name: "A", Kind: actors.Named})
+	actor.AddAction("act", func(ctx *actors.ActorContext, payload proto.Message) (actors.Value, error) {
+		handlerDeadline, hasDeadline = ctx.Context().Deadline()
+		return actors.Value{}, nil
+	})
+	s.RegisterActor(actor)
+
+	deadline := time.Now().Add(time.Hour)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	if _, err := s.InvokeContext(ctx, "test-system", "A", "act", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// The ActorHost receives the metadata sent by the caller to the proxy
+	decodeActorInvocationResponse(t, postActorInvocation(t, s, &protocol.ActorInvocation{
+		Actor:          &protocol.ActorId{Name: "A", System: "test-system"},
+		ActionName:     "act",
+		CurrentContext: &protocol.Context{Metadata: received.GetMetadata()},
+	}))
+	if !hasDeadline || !handlerDeadline.Equal(deadline) {
+		t.Errorf("handler deadline = %v, %v, want %v", handlerDeadline, hasDeadline, deadline)
+	}
+
+	// An invalid deadline is ignored
+	decodeActorInvocationResponse(t, postActorInvocation(t, s, &protocol.ActorInvocation{
+		Actor:          &protocol.ActorId{Name: "A", System: "test-system"},
+		ActionName:     "act",
+		CurrentContext: &protocol.Context{Metadata: map[string]string{DeadlineMetadataKey: "tomorrow"}},
+	}))
+	if hasDeadline {
+		t.Errorf("handler deadline = %v, want none", handlerDeadline)
+	}
+}
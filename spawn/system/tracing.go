@@ -0,0 +1,86 @@
+package system
+
+import (
+	"context"
+
+	protocol "github.com/eigr/spawn-go-sdk/spawn/eigr/functions/protocol/actors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// instrumentationName identifies the spans created by the system.
+const instrumentationName = "github.com/eigr/spawn-go-sdk/spawn/system"
+
+// tracePropagator carries the W3C trace context and baggage in the invocation metadata.
+var tracePropagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// WithTracerProvider enables OpenTelemetry tracing of the invocations and of the actions handled by the ActorHost.
+// Without it no span is recorded, but the trace context of the caller is still propagated to the invoked actors.
+func (s *System) WithTracerProvider(provider trace.TracerProvider) *System {
+	if provider == nil {
+		provider = noop.NewTracerProvider()
+	}
+	s.tracer = provider.Tracer(instrumentationName)
+	return s
+}
+
+// startClientSpan starts the span of an invocation and injects its trace context into a copy of the metadata.
+func (s *System) startClientSpan(ctx context.Context, req *protocol.InvocationRequest, invocationId string) (context.Context, trace.Span) {
+	ctx, span := s.tracer.Start(ctx, spanName(req.GetActor().GetId(), req.GetActionName()),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(s.spanAttributes(req.GetActor().GetId(), req.GetActionName(), invocationId)...),
+		trace.WithAttributes(attribute.Bool("spawn.async", req.GetAsync())),
+	)
+	req.Metadata = withTraceContext(ctx, req.Metadata)
+
+	return ctx, span
+}
+
+// withTraceContext returns a copy of the metadata carrying the trace context of ctx.
+func withTraceContext(ctx context.Context, metadata map[string]string) map[string]string {
+	carrier := make(propagation.MapCarrier, len(metadata)+2)
+	for key, value := range metadata {
+		carrier[key] = value
+	}
+	tracePropagator.Inject(ctx, carrier)
+	return carrier
+}
+
+// startServerSpan starts the span of an action handled by the ActorHost, as a child of the caller's span.
+func (s *System) startServerSpan(ctx context.Context, actorInvocation *protocol.ActorInvocation) (context.Context, trace.Span) {
+	metadata := actorInvocation.GetCurrentContext().GetMetadata()
+	ctx = tracePropagator.Extract(ctx, propagation.MapCarrier(metadata))
+
+	actorId := actorInvocation.GetActor()
+	actionName := actorInvocation.GetActionName()
+	return s.tracer.Start(ctx, spanName(actorId, actionName),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(s.spanAttributes(actorId, actionName, metadata[InvocationIdMetadataKey])...),
+	)
+}
+
+func (s *System) spanAttributes(actorId *protocol.ActorId, action string, invocationId string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("rpc.system", "spawn"),
+		attribute.String("rpc.service", registeredActorName(actorId)),
+		attribute.String("rpc.method", action),
+		attribute.String("spawn.system", s.name),
+		attribute.String("spawn.actor", actorId.GetName()),
+		attribute.String("spawn.invocation_id", invocationId),
+	}
+}
+
+// recordError marks the span as failed.
+func recordError(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// spanName names spans after the registered actor, as the instance names of Unnamed actors are unbounded.
+func spanName(actorId *protocol.ActorId, action string) string {
+	return registeredActorName(actorId) + "/" + action
+}
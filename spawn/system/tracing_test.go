@@ -0,0 +1,72 @@
+package system
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/eigr/spawn-go-sdk/spawn/actors"
+	protocol "github.com/eigr/spawn-go-sdk/spawn/eigr/functions/protocol/actors"
+
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+)
+
+// TestTracePropagation follows an invocation of an Unnamed instance from the client span
+// to the span of its handler, and from there to the side effects it issues.
+func TestTracePropagation(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer provider.Shutdown(context.Background())
+
+	actor := actors.ActorOf(actors.ActorConfig{Name: "UserActor", Kind: actors.Unnamed})
+	actor.AddAction("ChangeUserName", func(ctx *actors.ActorContext, payload proto.Message) (actors.Value, error) {
+		return actors.Of(nil).Effects(actors.SideEffect{Actor: "AuditActor", Action: "Record"}).Materialize(), nil
+	})
+	s := NewSystem("test-system").WithTracerProvider(provider)
+	s.RegisterActor(actor)
+
+	id := &protocol.ActorId{Name: "user-42", System: "test-system", Parent: "UserActor"}
+	req := &protocol.InvocationRequest{Actor: &protocol.Actor{Id: id}, ActionName: "ChangeUserName"}
+	_, clientSpan := s.startClientSpan(context.Background(), req, "invocation-1")
+	clientSpan.End()
+
+	resp, err := s.processActorInvocation(context.Background(), slog.New(discardHandler{}), &protocol.ActorInvocation{
+		Actor:          id,
+		ActionName:     "ChangeUserName",
+		CurrentContext: &protocol.Context{Metadata: req.Metadata},
+	})
+	if err != nil {
+		t.Fatalf("processActorInvocation() error = %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2", len(spans))
+	}
+	client, server := spans[0], spans[1]
+
+	for _, span := range spans {
+		if span.Name != "UserActor/ChangeUserName" {
+			t.Errorf("span name = %q, want %q", span.Name, "UserActor/ChangeUserName")
+		}
+	}
+	if client.SpanKind != trace.SpanKindClient || server.SpanKind != trace.SpanKindServer {
+		t.Errorf("span kinds = %v, %v, want client then server", client.SpanKind, server.SpanKind)
+	}
+	if server.Parent.SpanID() != client.SpanContext.SpanID() || server.SpanContext.TraceID() != client.SpanContext.TraceID() {
+		t.Errorf("server span parent = %v, want the client span %v", server.Parent, client.SpanContext)
+	}
+
+	effects := resp.GetWorkflow().GetEffects()
+	if len(effects) != 1 {
+		t.Fatalf("workflow has %d effects, want 1", len(effects))
+	}
+	effectCtx := tracePropagator.Extract(context.Background(), propagation.MapCarrier(effects[0].GetRequest().GetMetadata()))
+	if got := trace.SpanContextFromContext(effectCtx); got.SpanID() != server.SpanContext.SpanID() || got.TraceID() != server.SpanContext.TraceID() {
+		t.Errorf("side effect trace context = %v, want the server span %v", got, server.SpanContext)
+	}
+}
@@ -0,0 +1,205 @@
+package system
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/eigr/spawn-go-sdk/spawn/actors"
+)
+
+// ConfigError describes a single misconfiguration of a registered actor.
+type ConfigError struct {
+	Actor   string
+	Problem string
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("actor %q: %s", e.Actor, e.Problem)
+}
+
+// ValidationError lists every misconfiguration found in the registered actors.
+// Use errors.As to inspect the individual ConfigError values.
+type ValidationError struct {
+	Errors []*ConfigError
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid actor configuration (%d problems)", len(e.Errors))
+	for _, err := range e.Errors {
+		b.WriteString("\n  - ")
+		b.WriteString(err.Error())
+	}
+	return b.String()
+}
+
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Validate checks the configuration of every registered actor without contacting the proxy.
+// It returns a *ValidationError listing all the problems found, or nil.
+func (s *System) Validate() error {
+	if len(s.actors) == 0 {
+		return fmt.Errorf("no actors registered in the system")
+	}
+
+	var errs []*ConfigError
+	report := func(actor string, format string, args ...interface{}) {
+		errs = append(errs, &ConfigError{Actor: actor, Problem: fmt.Sprintf(format, args...)})
+	}
+
+	for _, name := range s.duplicateActors {
+		report(name, "registered more than once")
+	}
+
+	names := make([]string, 0, len(s.actors))
+	for name := range s.actors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		actor := s.actors[name]
+		validateSettings(actor, report)
+		validateActions(actor, report)
+		validateChannels(actor, report)
+		validateProjection(actor, report)
+	}
+
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+type problemReporter func(actor string, format string, args ...interface{})
+
+// validateSettings checks the kind and the runtime settings of the actor.
+func validateSettings(actor *actors.Actor, report problemReporter) {
+	if actor.Name == "" {
+		report(actor.Name, "name must not be empty")
+	}
+
+	if actor.Kind == "" {
+		report(actor.Name, "kind must be set, e.g. to actors.Named")
+	} else if kind, err := actors.ParseKind(string(actor.Kind)); err != nil {
+		report(actor.Name, "%v", err)
+	} else if kind != actor.Kind {
+		report(actor.Name, "kind %q must be written as %q, use actors.ParseKind for config-driven setups", actor.Kind, kind)
+	}
+
+	if actor.SnapshotTimeout < 0 {
+		report(actor.Name, "snapshot timeout must not be negative, got %d", actor.SnapshotTimeout)
+	}
+	if actor.DeactivatedTimeout < 0 {
+		report(actor.Name, "deactivated timeout must not be negative, got %d", actor.DeactivatedTimeout)
+	}
+
+	if actor.Kind != actors.Pooled {
+		if actor.MinPoolSize != 0 || actor.MaxPoolSize != 0 {
+			report(actor.Name, "pool sizes only apply to pooled actors")
+		}
+		return
+	}
+
+	if actor.Stateful {
+		report(actor.Name, "pooled actors cannot be stateful")
+	}
+	if actor.MinPoolSize < 0 || actor.MaxPoolSize < 0 {
+		report(actor.Name, "pool sizes must not be negative, got min %d and max %d", actor.MinPoolSize, actor.MaxPoolSize)
+	} else if actor.MaxPoolSize > 0 && actor.MinPoolSize > actor.MaxPoolSize {
+		report(actor.Name, "min pool size %d is greater than max pool size %d", actor.MinPoolSize, actor.MaxPoolSize)
+	}
+}
+
+// validateActions checks the actions and timer actions of the actor.
+func validateActions(actor *actors.Actor, report problemReporter) {
+	if len(actor.Actions) == 0 && len(actor.TimerActions) == 0 {
+		report(actor.Name, "no actions added")
+	}
+
+	for name, handler := range actor.Actions {
+		if name == "" {
+			report(actor.Name, "action name must not be empty")
+		}
+		if handler == nil {
+			report(actor.Name, "action %s has no handler", name)
+		}
+	}
+
+	for name, timer := range actor.TimerActions {
+		if _, ok := actor.Actions[name]; ok {
+			report(actor.Name, "timer action %s has the same name as an action", name)
+		}
+		if timer.Every <= 0 {
+			report(actor.Name, "timer action %s must have a positive interval, got %s", name, timer.Every)
+		}
+		if timer.Handler == nil {
+			report(actor.Name, "timer action %s has no handler", name)
+		}
+	}
+}
+
+// validateChannels ensures every channel subscription is routed to an action the actor actually handles.
+func validateChannels(actor *actors.Actor, report problemReporter) {
+	for _, channel := range actor.Channels {
+		if channel.Topic == "" {
+			report(actor.Name, "subscribes to a channel without a topic")
+			continue
+		}
+
+		action := channelAction(channel)
+		if _, ok := actor.Actions[action]; !ok {
+			report(actor.Name, "subscribes to topic %s with action %s, but that action was never added", channel.Topic, action)
+		}
+	}
+}
+
+// validateProjection ensures projection settings are consistent with the actor kind and its actions.
+func validateProjection(actor *actors.Actor, report problemReporter) {
+	projection := actor.ProjectionSettings
+	if projection == nil {
+		if actor.Kind == actors.Projection {
+			report(actor.Name, "projection actor has no projection settings")
+		}
+		return
+	}
+
+	if projection.EventsRetention < 0 && projection.EventsRetention != actors.InfiniteRetention {
+		report(actor.Name, "events retention must not be negative, use actors.InfiniteRetention to keep events forever")
+	}
+
+	if actor.Kind != actors.Projection {
+		if len(projection.Subjects) > 0 {
+			report(actor.Name, "defines projection subjects but is not a projection actor")
+		}
+		return
+	}
+
+	if len(projection.Subjects) == 0 {
+		report(actor.Name, "projection actor has no subjects")
+	}
+
+	for _, subject := range projection.Subjects {
+		if subject.Actor == "" {
+			report(actor.Name, "projection subject without an actor")
+		}
+		if _, ok := actor.Actions[subject.Action]; !ok {
+			report(actor.Name, "consumes %s with action %s, but that action was never added", subject.Actor, subject.Action)
+		}
+	}
+}
+
+// channelAction returns the action that handles messages published to the channel.
+func channelAction(channel actors.Channel) string {
+	if channel.Action == "" {
+		return actors.DefaultChannelAction
+	}
+	return channel.Action
+}
@@ -0,0 +1,152 @@
+package system
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/eigr/spawn-go-sdk/spawn/actors"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func noopHandler(ctx *actors.ActorContext, payload proto.Message) (actors.Value, error) {
+	return actors.Value{}, nil
+}
+
+func newTestActor(config actors.ActorConfig, actions ...string) *actors.Actor {
+	actor := actors.ActorOf(config)
+	for _, action := range actions {
+		actor.AddAction(action, noopHandler)
+	}
+	return actor
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		actors   []*actors.Actor
+		problems []string
+	}{
+		{
+			name:   "valid",
+			actors: []*actors.Actor{newTestActor(actors.ActorConfig{Name: "A", Kind: actors.Named}, "act")},
+		},
+		{
+			name:     "empty kind",
+			actors:   []*actors.Actor{newTestActor(actors.ActorConfig{Name: "A"}, "act")},
+			problems: []string{`actor "A": kind must be set`},
+		},
+		{
+			name:     "kind not normalized",
+			actors:   []*actors.Actor{newTestActor(actors.ActorConfig{Name: "A", Kind: "named"}, "act")},
+			problems: []string{`actor "A": kind "named" must be written as "NAMED"`},
+		},
+		{
+			name:     "unknown kind",
+			actors:   []*actors.Actor{newTestActor(actors.ActorConfig{Name: "A", Kind: "singleton"}, "act")},
+			problems: []string{`actor "A": unknown actor kind "singleton"`},
+		},
+		{
+			name: "duplicate",
+			actors: []*actors.Actor{
+				newTestActor(actors.ActorConfig{Name: "A", Kind: actors.Named}, "act"),
+				newTestActor(actors.ActorConfig{Name: "A", Kind: actors.Named}, "act"),
+			},
+			problems: []string{`actor "A": registered more than once`},
+		},
+		{
+			name:     "no actions",
+			actors:   []*actors.Actor{newTestActor(actors.ActorConfig{Name: "A", Kind: actors.Named})},
+			problems: []string{`actor "A": no actions added`},
+		},
+		{
+			name: "pooled",
+			actors: []*actors.Actor{newTestActor(actors.ActorConfig{
+				Name: "A", Kind: actors.Pooled, Stateful: true, MinPoolSize: 5, MaxPoolSize: 2,
+			}, "act")},
+			problems: []string{
+				`actor "A": pooled actors cannot be stateful`,
+				`actor "A": min pool size 5 is greater than max pool size 2`,
+			},
+		},
+		{
+			name: "channel without action",
+			actors: []*actors.Actor{newTestActor(actors.ActorConfig{
+				Name: "A", Kind: actors.Named, Channels: []actors.Channel{{Topic: "events"}},
+			}, "act")},
+			problems: []string{`actor "A": subscribes to topic events with action receive, but that action was never added`},
+		},
+		{
+			name:     "projection without settings",
+			actors:   []*actors.Actor{newTestActor(actors.ActorConfig{Name: "A", Kind: actors.Projection}, "act")},
+			problems: []string{`actor "A": projection actor has no projection settings`},
+		},
+		{
+			name: "problems of every actor are listed",
+			actors: func() []*actors.Actor {
+				timer := newTestActor(actors.ActorConfig{Name: "B", Kind: actors.Named}, "tick")
+				timer.AddTimerAction("tick", 0, noopHandler)
+				return []*actors.Actor{
+					newTestActor(actors.ActorConfig{Name: "C", Kind: actors.Named, SnapshotTimeout: -1}, "act"),
+					timer,
+					newTestActor(actors.ActorConfig{Name: "A", MinPoolSize: 1}, "act"),
+				}
+			}(),
+			problems: []string{
+				`actor "A": kind must be set`,
+				`actor "A": pool sizes only apply to pooled actors`,
+				`actor "B": timer action tick has the same name as an action`,
+				`actor "B": timer action tick must have a positive interval, got 0s`,
+				`actor "C": snapshot timeout must not be negative, got -1`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSystem("test")
+			for _, actor := range tt.actors {
+				s.RegisterActor(actor)
+			}
+
+			err := s.Validate()
+			if len(tt.problems) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Validate() = %v, want a *ValidationError", err)
+			}
+			if len(validationErr.Errors) != len(tt.problems) {
+				t.Fatalf("Validate() reported %d problems, want %d:\n%v", len(validationErr.Errors), len(tt.problems), err)
+			}
+			for i, problem := range tt.problems {
+				if got := validationErr.Errors[i].Error(); !strings.HasPrefix(got, problem) {
+					t.Errorf("problem %d = %q, want prefix %q", i, got, problem)
+				}
+			}
+
+			var configErr *ConfigError
+			if !errors.As(err, &configErr) {
+				t.Errorf("errors.As(%T, *ConfigError) = false", err)
+			}
+		})
+	}
+}
+
+func TestValidateNoActors(t *testing.T) {
+	err := NewSystem("test").Validate()
+	if err == nil {
+		t.Fatal("Validate() = nil, want an error")
+	}
+
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		t.Errorf("Validate() = %T, want a plain error", err)
+	}
+}
@@ -0,0 +1,120 @@
+package system
+
+import (
+	"fmt"
+
+	"github.com/eigr/spawn-go-sdk/spawn/actors"
+	protocol "github.com/eigr/spawn-go-sdk/spawn/eigr/functions/protocol/actors"
+
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// convertWorkflowToProtobuf converts the workflow returned by an action handler into its Protobuf representation.
+// The propagated metadata is added to every side effect, the Broadcast message has no metadata to carry it.
+func (s *System) convertWorkflowToProtobuf(workflow *actors.Workflow, propagatedMetadata map[string]string) (*protocol.Workflow, error) {
+	if workflow == nil {
+		return nil, nil
+	}
+
+	if workflow.Pipe != nil && workflow.Forward != nil {
+		return nil, fmt.Errorf("workflow cannot both pipe to %s and forward to %s", workflow.Pipe.Actor, workflow.Forward.Actor)
+	}
+
+	protoWorkflow := &protocol.Workflow{}
+
+	if workflow.Broadcast != nil {
+		broadcast, err := convertBroadcastToProtobuf(workflow.Broadcast)
+		if err != nil {
+			return nil, err
+		}
+		protoWorkflow.Broadcast = broadcast
+	}
+
+	for _, effect := range workflow.Effects {
+		sideEffect, err := s.convertSideEffectToProtobuf(effect, propagatedMetadata)
+		if err != nil {
+			return nil, err
+		}
+		protoWorkflow.Effects = append(protoWorkflow.Effects, sideEffect)
+	}
+
+	switch {
+	case workflow.Pipe != nil:
+		if workflow.Pipe.Actor == "" || workflow.Pipe.Action == "" {
+			return nil, fmt.Errorf("pipe must name both an actor and an action")
+		}
+		protoWorkflow.Routing = &protocol.Workflow_Pipe{
+			Pipe: &protocol.Pipe{Actor: workflow.Pipe.Actor, ActionName: workflow.Pipe.Action},
+		}
+	case workflow.Forward != nil:
+		if workflow.Forward.Actor == "" || workflow.Forward.Action == "" {
+			return nil, fmt.Errorf("forward must name both an actor and an action")
+		}
+		protoWorkflow.Routing = &protocol.Workflow_Forward{
+			Forward: &protocol.Forward{Actor: workflow.Forward.Actor, ActionName: workflow.Forward.Action},
+		}
+	}
+
+	return protoWorkflow, nil
+}
+
+func convertBroadcastToProtobuf(broadcast *actors.Broadcast) (*protocol.Broadcast, error) {
+	if broadcast.ChannelGroup == "" {
+		return nil, fmt.Errorf("broadcast channel group must not be empty")
+	}
+
+	protoBroadcast := &protocol.Broadcast{ChannelGroup: broadcast.ChannelGroup}
+
+	if broadcast.Payload == nil {
+		protoBroadcast.Payload = &protocol.Broadcast_Noop{Noop: &protocol.Noop{}}
+		return protoBroadcast, nil
+	}
+
+	payload, err := anypb.New(broadcast.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode broadcast payload for channel group %s: %w", broadcast.ChannelGroup, err)
+	}
+	protoBroadcast.Payload = &protocol.Broadcast_Value{Value: payload}
+
+	return protoBroadcast, nil
+}
+
+func (s *System) convertSideEffectToProtobuf(effect actors.SideEffect, propagatedMetadata map[string]string) (*protocol.SideEffect, error) {
+	if effect.Actor == "" || effect.Action == "" {
+		return nil, fmt.Errorf("side effect must name both an actor and an action")
+	}
+
+	system := effect.System
+	if system == "" {
+		system = s.name
+	}
+
+	req := &protocol.InvocationRequest{
+		System: &protocol.ActorSystem{Name: system},
+		Actor: &protocol.Actor{
+			Id: &protocol.ActorId{
+				Name:   effect.Actor,
+				System: system,
+			},
+		},
+		ActionName: effect.Action,
+		Async:      true,
+		Metadata:   mergeMetadata(propagatedMetadata, effect.Metadata),
+	}
+
+	if !effect.ScheduledTo.IsZero() {
+		req.ScheduledTo = effect.ScheduledTo.UnixMilli()
+	}
+
+	if effect.Payload == nil {
+		req.Payload = &protocol.InvocationRequest_Noop{Noop: &protocol.Noop{}}
+	} else {
+		payload, err := anypb.New(effect.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode side effect payload for actor %s: %w", effect.Actor, err)
+		}
+		req.Payload = &protocol.InvocationRequest_Value{Value: payload}
+	}
+
+	return &protocol.SideEffect{Request: req}, nil
+}
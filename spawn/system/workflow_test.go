@@ -0,0 +1,139 @@
+package system
+
+import (
+	"testing"
+	"time"
+
+	"github.com/eigr/spawn-go-sdk/spawn/actors"
+	protocol "github.com/eigr/spawn-go-sdk/spawn/eigr/functions/protocol/actors"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestConvertWorkflowToProtobuf(t *testing.T) {
+	scheduledTo := time.Date(2026, 1, 2, 3, 4, 5, 6_000_000, time.UTC)
+	payload, err := anypb.New(wrapperspb.String("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		workflow   *actors.Workflow
+		propagated map[string]string
+		want       *protocol.Workflow
+		wantErr    bool
+	}{
+		{
+			name: "no workflow",
+		},
+		{
+			name:     "broadcast without payload",
+			workflow: &actors.Workflow{Broadcast: &actors.Broadcast{ChannelGroup: "users"}},
+			want: &protocol.Workflow{Broadcast: &protocol.Broadcast{
+				ChannelGroup: "users",
+				Payload:      &protocol.Broadcast_Noop{Noop: &protocol.Noop{}},
+			}},
+		},
+		{
+			name:     "broadcast with payload",
+			workflow: &actors.Workflow{Broadcast: &actors.Broadcast{ChannelGroup: "users", Payload: wrapperspb.String("hello")}},
+			want: &protocol.Workflow{Broadcast: &protocol.Broadcast{
+				ChannelGroup: "users",
+				Payload:      &protocol.Broadcast_Value{Value: payload},
+			}},
+		},
+		{
+			name: "side effect defaults",
+			workflow: &actors.Workflow{Effects: []actors.SideEffect{{
+				Actor:    "AuditActor",
+				Action:   "Record",
+				Metadata: map[string]string{"tenant-id": "explicit", "request": "1"},
+			}}},
+			propagated: map[string]string{"tenant-id": "propagated", "correlation-id": "c-1"},
+			want: &protocol.Workflow{Effects: []*protocol.SideEffect{{Request: &protocol.InvocationRequest{
+				System:     &protocol.ActorSystem{Name: "test-system"},
+				Actor:      &protocol.Actor{Id: &protocol.ActorId{Name: "AuditActor", System: "test-system"}},
+				ActionName: "Record",
+				Async:      true,
+				Metadata:   map[string]string{"tenant-id": "explicit", "correlation-id": "c-1", "request": "1"},
+				Payload:    &protocol.InvocationRequest_Noop{Noop: &protocol.Noop{}},
+			}}}},
+		},
+		{
+			name: "scheduled side effect in another system",
+			workflow: &actors.Workflow{Effects: []actors.SideEffect{{
+				System:      "other-system",
+				Actor:       "AuditActor",
+				Action:      "Record",
+				Payload:     wrapperspb.String("hello"),
+				ScheduledTo: scheduledTo,
+			}}},
+			want: &protocol.Workflow{Effects: []*protocol.SideEffect{{Request: &protocol.InvocationRequest{
+				System:      &protocol.ActorSystem{Name: "other-system"},
+				Actor:       &protocol.Actor{Id: &protocol.ActorId{Name: "AuditActor", System: "other-system"}},
+				ActionName:  "Record",
+				Async:       true,
+				ScheduledTo: scheduledTo.UnixMilli(),
+				Payload:     &protocol.InvocationRequest_Value{Value: payload},
+			}}}},
+		},
+		{
+			name:     "pipe",
+			workflow: &actors.Workflow{Pipe: &actors.Pipe{Actor: "B", Action: "act"}},
+			want: &protocol.Workflow{Routing: &protocol.Workflow_Pipe{
+				Pipe: &protocol.Pipe{Actor: "B", ActionName: "act"},
+			}},
+		},
+		{
+			name:     "forward",
+			workflow: &actors.Workflow{Forward: &actors.Forward{Actor: "C", Action: "act"}},
+			want: &protocol.Workflow{Routing: &protocol.Workflow_Forward{
+				Forward: &protocol.Forward{Actor: "C", ActionName: "act"},
+			}},
+		},
+		{
+			name: "pipe and forward",
+			workflow: &actors.Workflow{
+				Pipe:    &actors.Pipe{Actor: "B", Action: "act"},
+				Forward: &actors.Forward{Actor: "C", Action: "act"},
+			},
+			wantErr: true,
+		},
+		{
+			name:     "pipe without action",
+			workflow: &actors.Workflow{Pipe: &actors.Pipe{Actor: "B"}},
+			wantErr:  true,
+		},
+		{
+			name:     "forward without actor",
+			workflow: &actors.Workflow{Forward: &actors.Forward{Action: "act"}},
+			wantErr:  true,
+		},
+		{
+			name:     "broadcast without channel group",
+			workflow: &actors.Workflow{Broadcast: &actors.Broadcast{}},
+			wantErr:  true,
+		},
+		{
+			name:     "side effect without action",
+			workflow: &actors.Workflow{Effects: []actors.SideEffect{{Actor: "AuditActor"}}},
+			wantErr:  true,
+		},
+	}
+
+	s := NewSystem("test-system")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.convertWorkflowToProtobuf(tt.workflow, tt.propagated)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("convertWorkflowToProtobuf() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !proto.Equal(got, tt.want) {
+				t.Errorf("convertWorkflowToProtobuf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}